@@ -17,7 +17,69 @@ type Config struct {
 	Magic      uint32
 	PrintLevel uint8
 	SeedList   []string
-	RPCPort    int
+	// MaxConnections is the maximum number of peers to connect, 10 by
+	// default.
+	MaxConnections int
+	// PowLimitBits is the proof of work limit in compact form to calculate
+	// the block difficulty, 0x1f0008ff of ELA main chain by default.
+	PowLimitBits uint32
+	// RPCPort is the TCP port of the RPC server, the RPC server will only
+	// serve on unix sockets if not specified.
+	RPCPort int
+	// RPCBind is the address the RPC server listens on, all interfaces
+	// if not specified.
+	RPCBind string
+	// RPCUsers are the credentials allowed to access the RPC server, the
+	// server is open to anyone if not specified.
+	RPCUsers []RPCUser
+	// RPCTLS enables HTTPS of the RPC server if specified
+	RPCTLS *TLS
+	// RPCUnix enables the RPC server on unix domain sockets if specified
+	RPCUnix *UnixSocket
+	// GRPCPort is the TCP port of the gRPC server, which listens on the
+	// RPCBind address, the gRPC server is disabled if not specified.
+	GRPCPort int
+	Webhooks []Webhook
+}
+
+type UnixSocket struct {
+	// Path of the socket to serve HTTP JSON-RPC, REST and WebSocket
+	Path string
+	// RawPath of the socket to serve newline-delimited JSON-RPC
+	RawPath string
+	// Mode is the octal file permissions of the sockets, "0600" by default
+	Mode string
+}
+
+type TLS struct {
+	// CertFile and KeyFile are the PEM encoded certificate and private key,
+	// a self-signed certificate will be generated if they do not exist.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the PEM encoded CA bundle, clients must present a
+	// certificate signed by them if specified.
+	ClientCAFile string
+}
+
+type RPCUser struct {
+	// Name identifies the credential, and is the user name of basic auth
+	Name string
+	// Password for HTTP basic auth, basic auth is disabled if not specified
+	Password string
+	// Token for HTTP bearer auth, bearer auth is disabled if not specified
+	Token string
+	// Methods allowed to call, all methods are allowed if not specified
+	Methods []string
+}
+
+type Webhook struct {
+	URL    string
+	Secret string
+	// Events to deliver, "transaction" "confirmation" and "rollback",
+	// all events will be delivered if not specified.
+	Events []string
+	// Confirmations for the "confirmation" event
+	Confirmations uint32
 }
 
 func (config *Config) readConfigFile() error {
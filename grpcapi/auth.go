@@ -0,0 +1,83 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/grpcapi/pb"
+	"github.com/elastos/Elastos.ELA.SPV.Node/rpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// rpcMethods maps the gRPC methods to the JSON-RPC methods serving the same
+// purpose, so the method allowlists of RPC users apply to both.
+var rpcMethods = map[string]string{
+	pb.SPV_GetBestHeader_FullMethodName:         "getblockheader",
+	pb.SPV_GetHeader_FullMethodName:             "getblockheader",
+	pb.SPV_GetBlock_FullMethodName:              "getblock",
+	pb.SPV_GetTransaction_FullMethodName:        "getrawtransaction",
+	pb.SPV_RegisterAddress_FullMethodName:       "registeraddress",
+	pb.SPV_UnregisterAddress_FullMethodName:     "unregisteraddress",
+	pb.SPV_GetAddresses_FullMethodName:          "getaddresses",
+	pb.SPV_ListUnspent_FullMethodName:           "listunspent",
+	pb.SPV_GetBalance_FullMethodName:            "getbalance",
+	pb.SPV_SendRawTransaction_FullMethodName:    "sendrawtransaction",
+	pb.SPV_SubscribeBlocks_FullMethodName:       "subscribe",
+	pb.SPV_SubscribeTransactions_FullMethodName: "subscribe",
+}
+
+// authorize checks the authorization metadata of the call with the RPC
+// users, the method allowlist matches the JSON-RPC method of the gRPC
+// method, like "getrawtransaction" for /spv.SPV/GetTransaction.
+func authorize(ctx context.Context, authorizer *rpc.Authorizer, fullMethod string) error {
+	if authorizer == nil {
+		return nil
+	}
+
+	var authorization, remote string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remote = p.Addr.String()
+	}
+	method, ok := rpcMethods[fullMethod]
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", fullMethod)
+	}
+
+	switch err := authorizer.Authorize(authorization, remote, method); err {
+	case nil:
+		return nil
+	case rpc.ErrUnauthorized:
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+}
+
+func unaryAuth(authorizer *rpc.Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, authorizer, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(authorizer *rpc.Authorizer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := authorize(stream.Context(), authorizer, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
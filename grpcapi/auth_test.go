@@ -0,0 +1,63 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+	"github.com/elastos/Elastos.ELA.SPV.Node/rpc"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuth(t *testing.T) {
+	authorizer := rpc.NewAuthorizer([]config.RPCUser{
+		{Name: "admin", Password: "secret"},
+		{Name: "reader", Token: "token", Methods: []string{"getblock", "getrawtransaction"}},
+	})
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:secret"))
+
+	cases := []struct {
+		authorization string
+		method        string
+		code          codes.Code
+	}{
+		{basic, "/spv.SPV/SendRawTransaction", codes.OK},
+		{"Bearer token", "/spv.SPV/GetBlock", codes.OK},
+		{"Bearer token", "/spv.SPV/GetTransaction", codes.OK},
+		{"Bearer token", "/spv.SPV/SendRawTransaction", codes.PermissionDenied},
+		{basic, "/spv.SPV/Unknown", codes.Unimplemented},
+		{"Bearer wrong", "/spv.SPV/GetBlock", codes.Unauthenticated},
+		{"", "/spv.SPV/GetBlock", codes.Unauthenticated},
+	}
+	for _, c := range cases {
+		ctx := context.Background()
+		if len(c.authorization) > 0 {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", c.authorization))
+		}
+		var called bool
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			called = true
+			return nil, nil
+		}
+		_, err := unaryAuth(authorizer)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: c.method}, handler)
+		if code := status.Code(err); code != c.code {
+			t.Fatalf("%s with %q got code %s, expect %s", c.method, c.authorization, code, c.code)
+		}
+		if called != (c.code == codes.OK) {
+			t.Fatalf("%s with %q handler called %v", c.method, c.authorization, called)
+		}
+	}
+
+	// All calls are accepted without RPC users
+	_, err := unaryAuth(rpc.NewAuthorizer(nil))(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/spv.SPV/GetBlock"},
+		func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+}
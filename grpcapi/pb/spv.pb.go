@@ -0,0 +1,1755 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: spv.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Header struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Hash              string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Height            uint32                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Version           uint32                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	PreviousBlockHash string                 `protobuf:"bytes,4,opt,name=previous_block_hash,json=previousBlockHash,proto3" json:"previous_block_hash,omitempty"`
+	MerkleRoot        string                 `protobuf:"bytes,5,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Time              uint32                 `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+	Bits              uint32                 `protobuf:"varint,7,opt,name=bits,proto3" json:"bits,omitempty"`
+	Nonce             uint32                 `protobuf:"varint,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Confirmations     uint32                 `protobuf:"varint,9,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	// Serialized header including the auxpow
+	Raw           []byte `protobuf:"bytes,10,opt,name=raw,proto3" json:"raw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Header) Reset() {
+	*x = Header{}
+	mi := &file_spv_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Header) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Header) ProtoMessage() {}
+
+func (x *Header) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Header.ProtoReflect.Descriptor instead.
+func (*Header) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Header) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Header) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Header) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Header) GetPreviousBlockHash() string {
+	if x != nil {
+		return x.PreviousBlockHash
+	}
+	return ""
+}
+
+func (x *Header) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *Header) GetTime() uint32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Header) GetBits() uint32 {
+	if x != nil {
+		return x.Bits
+	}
+	return 0
+}
+
+func (x *Header) GetNonce() uint32 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *Header) GetConfirmations() uint32 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+func (x *Header) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
+type Input struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Vout          uint32                 `protobuf:"varint,2,opt,name=vout,proto3" json:"vout,omitempty"`
+	Sequence      uint32                 `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Input) Reset() {
+	*x = Input{}
+	mi := &file_spv_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Input) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Input) ProtoMessage() {}
+
+func (x *Input) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Input.ProtoReflect.Descriptor instead.
+func (*Input) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Input) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *Input) GetVout() uint32 {
+	if x != nil {
+		return x.Vout
+	}
+	return 0
+}
+
+func (x *Input) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type Output struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Value         int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	AssetId       string                 `protobuf:"bytes,4,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	OutputLock    uint32                 `protobuf:"varint,5,opt,name=output_lock,json=outputLock,proto3" json:"output_lock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Output) Reset() {
+	*x = Output{}
+	mi := &file_spv_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Output) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Output) ProtoMessage() {}
+
+func (x *Output) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Output.ProtoReflect.Descriptor instead.
+func (*Output) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Output) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Output) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Output) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Output) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *Output) GetOutputLock() uint32 {
+	if x != nil {
+		return x.OutputLock
+	}
+	return 0
+}
+
+type Transaction struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Txid           string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Type           uint32                 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	PayloadVersion uint32                 `protobuf:"varint,3,opt,name=payload_version,json=payloadVersion,proto3" json:"payload_version,omitempty"`
+	LockTime       uint32                 `protobuf:"varint,4,opt,name=lock_time,json=lockTime,proto3" json:"lock_time,omitempty"`
+	Inputs         []*Input               `protobuf:"bytes,5,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs        []*Output              `protobuf:"bytes,6,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	// Block hash and height, empty for unconfirmed transactions
+	BlockHash     string `protobuf:"bytes,7,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Height        uint32 `protobuf:"varint,8,opt,name=height,proto3" json:"height,omitempty"`
+	Confirmations uint32 `protobuf:"varint,9,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	// Serialized transaction
+	Raw           []byte `protobuf:"bytes,10,opt,name=raw,proto3" json:"raw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_spv_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Transaction) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *Transaction) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *Transaction) GetPayloadVersion() uint32 {
+	if x != nil {
+		return x.PayloadVersion
+	}
+	return 0
+}
+
+func (x *Transaction) GetLockTime() uint32 {
+	if x != nil {
+		return x.LockTime
+	}
+	return 0
+}
+
+func (x *Transaction) GetInputs() []*Input {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *Transaction) GetOutputs() []*Output {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *Transaction) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *Transaction) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Transaction) GetConfirmations() uint32 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+func (x *Transaction) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
+type Block struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Header *Header                `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// Transactions matched the registered addresses
+	Transactions  []*Transaction `protobuf:"bytes,2,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_spv_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Block) GetHeader() *Header {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *Block) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+type GetBestHeaderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBestHeaderRequest) Reset() {
+	*x = GetBestHeaderRequest{}
+	mi := &file_spv_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBestHeaderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBestHeaderRequest) ProtoMessage() {}
+
+func (x *GetBestHeaderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBestHeaderRequest.ProtoReflect.Descriptor instead.
+func (*GetBestHeaderRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{5}
+}
+
+type GetHeaderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Block:
+	//
+	//	*GetHeaderRequest_Hash
+	//	*GetHeaderRequest_Height
+	Block         isGetHeaderRequest_Block `protobuf_oneof:"block"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHeaderRequest) Reset() {
+	*x = GetHeaderRequest{}
+	mi := &file_spv_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHeaderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHeaderRequest) ProtoMessage() {}
+
+func (x *GetHeaderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHeaderRequest.ProtoReflect.Descriptor instead.
+func (*GetHeaderRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetHeaderRequest) GetBlock() isGetHeaderRequest_Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *GetHeaderRequest) GetHash() string {
+	if x != nil {
+		if x, ok := x.Block.(*GetHeaderRequest_Hash); ok {
+			return x.Hash
+		}
+	}
+	return ""
+}
+
+func (x *GetHeaderRequest) GetHeight() uint32 {
+	if x != nil {
+		if x, ok := x.Block.(*GetHeaderRequest_Height); ok {
+			return x.Height
+		}
+	}
+	return 0
+}
+
+type isGetHeaderRequest_Block interface {
+	isGetHeaderRequest_Block()
+}
+
+type GetHeaderRequest_Hash struct {
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3,oneof"`
+}
+
+type GetHeaderRequest_Height struct {
+	Height uint32 `protobuf:"varint,2,opt,name=height,proto3,oneof"`
+}
+
+func (*GetHeaderRequest_Hash) isGetHeaderRequest_Block() {}
+
+func (*GetHeaderRequest_Height) isGetHeaderRequest_Block() {}
+
+type GetBlockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Block:
+	//
+	//	*GetBlockRequest_Hash
+	//	*GetBlockRequest_Height
+	Block         isGetBlockRequest_Block `protobuf_oneof:"block"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockRequest) Reset() {
+	*x = GetBlockRequest{}
+	mi := &file_spv_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockRequest) ProtoMessage() {}
+
+func (x *GetBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetBlockRequest) GetBlock() isGetBlockRequest_Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *GetBlockRequest) GetHash() string {
+	if x != nil {
+		if x, ok := x.Block.(*GetBlockRequest_Hash); ok {
+			return x.Hash
+		}
+	}
+	return ""
+}
+
+func (x *GetBlockRequest) GetHeight() uint32 {
+	if x != nil {
+		if x, ok := x.Block.(*GetBlockRequest_Height); ok {
+			return x.Height
+		}
+	}
+	return 0
+}
+
+type isGetBlockRequest_Block interface {
+	isGetBlockRequest_Block()
+}
+
+type GetBlockRequest_Hash struct {
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3,oneof"`
+}
+
+type GetBlockRequest_Height struct {
+	Height uint32 `protobuf:"varint,2,opt,name=height,proto3,oneof"`
+}
+
+func (*GetBlockRequest_Hash) isGetBlockRequest_Block() {}
+
+func (*GetBlockRequest_Height) isGetBlockRequest_Block() {}
+
+type GetTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransactionRequest) Reset() {
+	*x = GetTransactionRequest{}
+	mi := &file_spv_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionRequest) ProtoMessage() {}
+
+func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTransactionRequest) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+type RegisterAddressRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Address string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Label   string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	// Block height, or unix timestamp if not less than 500000000, the
+	// historical transactions since it will be synchronized.
+	Birthday      uint32 `protobuf:"varint,3,opt,name=birthday,proto3" json:"birthday,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAddressRequest) Reset() {
+	*x = RegisterAddressRequest{}
+	mi := &file_spv_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAddressRequest) ProtoMessage() {}
+
+func (x *RegisterAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAddressRequest.ProtoReflect.Descriptor instead.
+func (*RegisterAddressRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RegisterAddressRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *RegisterAddressRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *RegisterAddressRequest) GetBirthday() uint32 {
+	if x != nil {
+		return x.Birthday
+	}
+	return 0
+}
+
+type RegisterAddressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAddressResponse) Reset() {
+	*x = RegisterAddressResponse{}
+	mi := &file_spv_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAddressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAddressResponse) ProtoMessage() {}
+
+func (x *RegisterAddressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAddressResponse.ProtoReflect.Descriptor instead.
+func (*RegisterAddressResponse) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{10}
+}
+
+type UnregisterAddressRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Address string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// Delete the stored transactions and outputs of the address
+	Purge         bool `protobuf:"varint,2,opt,name=purge,proto3" json:"purge,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterAddressRequest) Reset() {
+	*x = UnregisterAddressRequest{}
+	mi := &file_spv_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterAddressRequest) ProtoMessage() {}
+
+func (x *UnregisterAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterAddressRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterAddressRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnregisterAddressRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *UnregisterAddressRequest) GetPurge() bool {
+	if x != nil {
+		return x.Purge
+	}
+	return false
+}
+
+type UnregisterAddressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterAddressResponse) Reset() {
+	*x = UnregisterAddressResponse{}
+	mi := &file_spv_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterAddressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterAddressResponse) ProtoMessage() {}
+
+func (x *UnregisterAddressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterAddressResponse.ProtoReflect.Descriptor instead.
+func (*UnregisterAddressResponse) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{12}
+}
+
+type GetAddressesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAddressesRequest) Reset() {
+	*x = GetAddressesRequest{}
+	mi := &file_spv_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAddressesRequest) ProtoMessage() {}
+
+func (x *GetAddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAddressesRequest.ProtoReflect.Descriptor instead.
+func (*GetAddressesRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{13}
+}
+
+type Address struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Address        string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Label          string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	RegisterTime   int64                  `protobuf:"varint,3,opt,name=register_time,json=registerTime,proto3" json:"register_time,omitempty"`
+	RegisterHeight uint32                 `protobuf:"varint,4,opt,name=register_height,json=registerHeight,proto3" json:"register_height,omitempty"`
+	Birthday       uint32                 `protobuf:"varint,5,opt,name=birthday,proto3" json:"birthday,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_spv_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Address) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Address) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Address) GetRegisterTime() int64 {
+	if x != nil {
+		return x.RegisterTime
+	}
+	return 0
+}
+
+func (x *Address) GetRegisterHeight() uint32 {
+	if x != nil {
+		return x.RegisterHeight
+	}
+	return 0
+}
+
+func (x *Address) GetBirthday() uint32 {
+	if x != nil {
+		return x.Birthday
+	}
+	return 0
+}
+
+type GetAddressesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addresses     []*Address             `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAddressesResponse) Reset() {
+	*x = GetAddressesResponse{}
+	mi := &file_spv_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAddressesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAddressesResponse) ProtoMessage() {}
+
+func (x *GetAddressesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAddressesResponse.ProtoReflect.Descriptor instead.
+func (*GetAddressesResponse) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAddressesResponse) GetAddresses() []*Address {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type UTXO struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Vout          uint32                 `protobuf:"varint,2,opt,name=vout,proto3" json:"vout,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Value         int64                  `protobuf:"varint,4,opt,name=value,proto3" json:"value,omitempty"`
+	AssetId       string                 `protobuf:"bytes,5,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	OutputLock    uint32                 `protobuf:"varint,6,opt,name=output_lock,json=outputLock,proto3" json:"output_lock,omitempty"`
+	Height        uint32                 `protobuf:"varint,7,opt,name=height,proto3" json:"height,omitempty"`
+	Confirmations uint32                 `protobuf:"varint,8,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UTXO) Reset() {
+	*x = UTXO{}
+	mi := &file_spv_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UTXO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTXO) ProtoMessage() {}
+
+func (x *UTXO) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTXO.ProtoReflect.Descriptor instead.
+func (*UTXO) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UTXO) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *UTXO) GetVout() uint32 {
+	if x != nil {
+		return x.Vout
+	}
+	return 0
+}
+
+func (x *UTXO) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *UTXO) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *UTXO) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *UTXO) GetOutputLock() uint32 {
+	if x != nil {
+		return x.OutputLock
+	}
+	return 0
+}
+
+func (x *UTXO) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *UTXO) GetConfirmations() uint32 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+type ListUnspentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All registered addresses if empty
+	Addresses []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	MinConf   uint32   `protobuf:"varint,2,opt,name=min_conf,json=minConf,proto3" json:"min_conf,omitempty"`
+	// No limit if zero
+	MaxConf uint32 `protobuf:"varint,3,opt,name=max_conf,json=maxConf,proto3" json:"max_conf,omitempty"`
+	// All assets if empty
+	AssetId       string `protobuf:"bytes,4,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUnspentRequest) Reset() {
+	*x = ListUnspentRequest{}
+	mi := &file_spv_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUnspentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUnspentRequest) ProtoMessage() {}
+
+func (x *ListUnspentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUnspentRequest.ProtoReflect.Descriptor instead.
+func (*ListUnspentRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListUnspentRequest) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *ListUnspentRequest) GetMinConf() uint32 {
+	if x != nil {
+		return x.MinConf
+	}
+	return 0
+}
+
+func (x *ListUnspentRequest) GetMaxConf() uint32 {
+	if x != nil {
+		return x.MaxConf
+	}
+	return 0
+}
+
+func (x *ListUnspentRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+type ListUnspentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Utxos         []*UTXO                `protobuf:"bytes,1,rep,name=utxos,proto3" json:"utxos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUnspentResponse) Reset() {
+	*x = ListUnspentResponse{}
+	mi := &file_spv_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUnspentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUnspentResponse) ProtoMessage() {}
+
+func (x *ListUnspentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUnspentResponse.ProtoReflect.Descriptor instead.
+func (*ListUnspentResponse) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListUnspentResponse) GetUtxos() []*UTXO {
+	if x != nil {
+		return x.Utxos
+	}
+	return nil
+}
+
+type GetBalanceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All registered addresses if empty
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	MinConf uint32 `protobuf:"varint,2,opt,name=min_conf,json=minConf,proto3" json:"min_conf,omitempty"`
+	// ELA if empty
+	AssetId       string `protobuf:"bytes,3,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_spv_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetBalanceRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *GetBalanceRequest) GetMinConf() uint32 {
+	if x != nil {
+		return x.MinConf
+	}
+	return 0
+}
+
+func (x *GetBalanceRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+type GetBalanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       int64                  `protobuf:"varint,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceResponse) Reset() {
+	*x = GetBalanceResponse{}
+	mi := &file_spv_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceResponse) ProtoMessage() {}
+
+func (x *GetBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceResponse.ProtoReflect.Descriptor instead.
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetBalanceResponse) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+type SendRawTransactionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Serialized ELA transaction
+	Raw           []byte `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendRawTransactionRequest) Reset() {
+	*x = SendRawTransactionRequest{}
+	mi := &file_spv_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendRawTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendRawTransactionRequest) ProtoMessage() {}
+
+func (x *SendRawTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendRawTransactionRequest.ProtoReflect.Descriptor instead.
+func (*SendRawTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SendRawTransactionRequest) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
+type SendRawTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendRawTransactionResponse) Reset() {
+	*x = SendRawTransactionResponse{}
+	mi := &file_spv_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendRawTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendRawTransactionResponse) ProtoMessage() {}
+
+func (x *SendRawTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendRawTransactionResponse.ProtoReflect.Descriptor instead.
+func (*SendRawTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SendRawTransactionResponse) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+type SubscribeBlocksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeBlocksRequest) Reset() {
+	*x = SubscribeBlocksRequest{}
+	mi := &file_spv_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeBlocksRequest) ProtoMessage() {}
+
+func (x *SubscribeBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeBlocksRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{23}
+}
+
+type SubscribeTransactionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All registered addresses if empty
+	Addresses     []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeTransactionsRequest) Reset() {
+	*x = SubscribeTransactionsRequest{}
+	mi := &file_spv_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeTransactionsRequest) ProtoMessage() {}
+
+func (x *SubscribeTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spv_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_spv_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SubscribeTransactionsRequest) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+var File_spv_proto protoreflect.FileDescriptor
+
+const file_spv_proto_rawDesc = "" +
+	"\n" +
+	"\tspv.proto\x12\x03spv\"\x95\x02\n" +
+	"\x06Header\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\rR\x06height\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\rR\aversion\x12.\n" +
+	"\x13previous_block_hash\x18\x04 \x01(\tR\x11previousBlockHash\x12\x1f\n" +
+	"\vmerkle_root\x18\x05 \x01(\tR\n" +
+	"merkleRoot\x12\x12\n" +
+	"\x04time\x18\x06 \x01(\rR\x04time\x12\x12\n" +
+	"\x04bits\x18\a \x01(\rR\x04bits\x12\x14\n" +
+	"\x05nonce\x18\b \x01(\rR\x05nonce\x12$\n" +
+	"\rconfirmations\x18\t \x01(\rR\rconfirmations\x12\x10\n" +
+	"\x03raw\x18\n" +
+	" \x01(\fR\x03raw\"K\n" +
+	"\x05Input\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x12\n" +
+	"\x04vout\x18\x02 \x01(\rR\x04vout\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\rR\bsequence\"\x8a\x01\n" +
+	"\x06Output\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x19\n" +
+	"\basset_id\x18\x04 \x01(\tR\aassetId\x12\x1f\n" +
+	"\voutput_lock\x18\x05 \x01(\rR\n" +
+	"outputLock\"\xb5\x02\n" +
+	"\vTransaction\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\rR\x04type\x12'\n" +
+	"\x0fpayload_version\x18\x03 \x01(\rR\x0epayloadVersion\x12\x1b\n" +
+	"\tlock_time\x18\x04 \x01(\rR\blockTime\x12\"\n" +
+	"\x06inputs\x18\x05 \x03(\v2\n" +
+	".spv.InputR\x06inputs\x12%\n" +
+	"\aoutputs\x18\x06 \x03(\v2\v.spv.OutputR\aoutputs\x12\x1d\n" +
+	"\n" +
+	"block_hash\x18\a \x01(\tR\tblockHash\x12\x16\n" +
+	"\x06height\x18\b \x01(\rR\x06height\x12$\n" +
+	"\rconfirmations\x18\t \x01(\rR\rconfirmations\x12\x10\n" +
+	"\x03raw\x18\n" +
+	" \x01(\fR\x03raw\"b\n" +
+	"\x05Block\x12#\n" +
+	"\x06header\x18\x01 \x01(\v2\v.spv.HeaderR\x06header\x124\n" +
+	"\ftransactions\x18\x02 \x03(\v2\x10.spv.TransactionR\ftransactions\"\x16\n" +
+	"\x14GetBestHeaderRequest\"K\n" +
+	"\x10GetHeaderRequest\x12\x14\n" +
+	"\x04hash\x18\x01 \x01(\tH\x00R\x04hash\x12\x18\n" +
+	"\x06height\x18\x02 \x01(\rH\x00R\x06heightB\a\n" +
+	"\x05block\"J\n" +
+	"\x0fGetBlockRequest\x12\x14\n" +
+	"\x04hash\x18\x01 \x01(\tH\x00R\x04hash\x12\x18\n" +
+	"\x06height\x18\x02 \x01(\rH\x00R\x06heightB\a\n" +
+	"\x05block\"+\n" +
+	"\x15GetTransactionRequest\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\"d\n" +
+	"\x16RegisterAddressRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x1a\n" +
+	"\bbirthday\x18\x03 \x01(\rR\bbirthday\"\x19\n" +
+	"\x17RegisterAddressResponse\"J\n" +
+	"\x18UnregisterAddressRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05purge\x18\x02 \x01(\bR\x05purge\"\x1b\n" +
+	"\x19UnregisterAddressResponse\"\x15\n" +
+	"\x13GetAddressesRequest\"\xa3\x01\n" +
+	"\aAddress\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12#\n" +
+	"\rregister_time\x18\x03 \x01(\x03R\fregisterTime\x12'\n" +
+	"\x0fregister_height\x18\x04 \x01(\rR\x0eregisterHeight\x12\x1a\n" +
+	"\bbirthday\x18\x05 \x01(\rR\bbirthday\"B\n" +
+	"\x14GetAddressesResponse\x12*\n" +
+	"\taddresses\x18\x01 \x03(\v2\f.spv.AddressR\taddresses\"\xd8\x01\n" +
+	"\x04UTXO\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x12\n" +
+	"\x04vout\x18\x02 \x01(\rR\x04vout\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x03R\x05value\x12\x19\n" +
+	"\basset_id\x18\x05 \x01(\tR\aassetId\x12\x1f\n" +
+	"\voutput_lock\x18\x06 \x01(\rR\n" +
+	"outputLock\x12\x16\n" +
+	"\x06height\x18\a \x01(\rR\x06height\x12$\n" +
+	"\rconfirmations\x18\b \x01(\rR\rconfirmations\"\x83\x01\n" +
+	"\x12ListUnspentRequest\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\x12\x19\n" +
+	"\bmin_conf\x18\x02 \x01(\rR\aminConf\x12\x19\n" +
+	"\bmax_conf\x18\x03 \x01(\rR\amaxConf\x12\x19\n" +
+	"\basset_id\x18\x04 \x01(\tR\aassetId\"6\n" +
+	"\x13ListUnspentResponse\x12\x1f\n" +
+	"\x05utxos\x18\x01 \x03(\v2\t.spv.UTXOR\x05utxos\"c\n" +
+	"\x11GetBalanceRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x19\n" +
+	"\bmin_conf\x18\x02 \x01(\rR\aminConf\x12\x19\n" +
+	"\basset_id\x18\x03 \x01(\tR\aassetId\".\n" +
+	"\x12GetBalanceResponse\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\x03R\abalance\"-\n" +
+	"\x19SendRawTransactionRequest\x12\x10\n" +
+	"\x03raw\x18\x01 \x01(\fR\x03raw\"0\n" +
+	"\x1aSendRawTransactionResponse\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\"\x18\n" +
+	"\x16SubscribeBlocksRequest\"<\n" +
+	"\x1cSubscribeTransactionsRequest\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses2\xab\x06\n" +
+	"\x03SPV\x127\n" +
+	"\rGetBestHeader\x12\x19.spv.GetBestHeaderRequest\x1a\v.spv.Header\x12/\n" +
+	"\tGetHeader\x12\x15.spv.GetHeaderRequest\x1a\v.spv.Header\x12,\n" +
+	"\bGetBlock\x12\x14.spv.GetBlockRequest\x1a\n" +
+	".spv.Block\x12>\n" +
+	"\x0eGetTransaction\x12\x1a.spv.GetTransactionRequest\x1a\x10.spv.Transaction\x12L\n" +
+	"\x0fRegisterAddress\x12\x1b.spv.RegisterAddressRequest\x1a\x1c.spv.RegisterAddressResponse\x12R\n" +
+	"\x11UnregisterAddress\x12\x1d.spv.UnregisterAddressRequest\x1a\x1e.spv.UnregisterAddressResponse\x12C\n" +
+	"\fGetAddresses\x12\x18.spv.GetAddressesRequest\x1a\x19.spv.GetAddressesResponse\x12@\n" +
+	"\vListUnspent\x12\x17.spv.ListUnspentRequest\x1a\x18.spv.ListUnspentResponse\x12=\n" +
+	"\n" +
+	"GetBalance\x12\x16.spv.GetBalanceRequest\x1a\x17.spv.GetBalanceResponse\x12U\n" +
+	"\x12SendRawTransaction\x12\x1e.spv.SendRawTransactionRequest\x1a\x1f.spv.SendRawTransactionResponse\x12=\n" +
+	"\x0fSubscribeBlocks\x12\x1b.spv.SubscribeBlocksRequest\x1a\v.spv.Header0\x01\x12N\n" +
+	"\x15SubscribeTransactions\x12!.spv.SubscribeTransactionsRequest\x1a\x10.spv.Transaction0\x01B7Z5github.com/elastos/Elastos.ELA.SPV.Node/grpcapi/pb;pbb\x06proto3"
+
+var (
+	file_spv_proto_rawDescOnce sync.Once
+	file_spv_proto_rawDescData []byte
+)
+
+func file_spv_proto_rawDescGZIP() []byte {
+	file_spv_proto_rawDescOnce.Do(func() {
+		file_spv_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_spv_proto_rawDesc), len(file_spv_proto_rawDesc)))
+	})
+	return file_spv_proto_rawDescData
+}
+
+var file_spv_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_spv_proto_goTypes = []any{
+	(*Header)(nil),                       // 0: spv.Header
+	(*Input)(nil),                        // 1: spv.Input
+	(*Output)(nil),                       // 2: spv.Output
+	(*Transaction)(nil),                  // 3: spv.Transaction
+	(*Block)(nil),                        // 4: spv.Block
+	(*GetBestHeaderRequest)(nil),         // 5: spv.GetBestHeaderRequest
+	(*GetHeaderRequest)(nil),             // 6: spv.GetHeaderRequest
+	(*GetBlockRequest)(nil),              // 7: spv.GetBlockRequest
+	(*GetTransactionRequest)(nil),        // 8: spv.GetTransactionRequest
+	(*RegisterAddressRequest)(nil),       // 9: spv.RegisterAddressRequest
+	(*RegisterAddressResponse)(nil),      // 10: spv.RegisterAddressResponse
+	(*UnregisterAddressRequest)(nil),     // 11: spv.UnregisterAddressRequest
+	(*UnregisterAddressResponse)(nil),    // 12: spv.UnregisterAddressResponse
+	(*GetAddressesRequest)(nil),          // 13: spv.GetAddressesRequest
+	(*Address)(nil),                      // 14: spv.Address
+	(*GetAddressesResponse)(nil),         // 15: spv.GetAddressesResponse
+	(*UTXO)(nil),                         // 16: spv.UTXO
+	(*ListUnspentRequest)(nil),           // 17: spv.ListUnspentRequest
+	(*ListUnspentResponse)(nil),          // 18: spv.ListUnspentResponse
+	(*GetBalanceRequest)(nil),            // 19: spv.GetBalanceRequest
+	(*GetBalanceResponse)(nil),           // 20: spv.GetBalanceResponse
+	(*SendRawTransactionRequest)(nil),    // 21: spv.SendRawTransactionRequest
+	(*SendRawTransactionResponse)(nil),   // 22: spv.SendRawTransactionResponse
+	(*SubscribeBlocksRequest)(nil),       // 23: spv.SubscribeBlocksRequest
+	(*SubscribeTransactionsRequest)(nil), // 24: spv.SubscribeTransactionsRequest
+}
+var file_spv_proto_depIdxs = []int32{
+	1,  // 0: spv.Transaction.inputs:type_name -> spv.Input
+	2,  // 1: spv.Transaction.outputs:type_name -> spv.Output
+	0,  // 2: spv.Block.header:type_name -> spv.Header
+	3,  // 3: spv.Block.transactions:type_name -> spv.Transaction
+	14, // 4: spv.GetAddressesResponse.addresses:type_name -> spv.Address
+	16, // 5: spv.ListUnspentResponse.utxos:type_name -> spv.UTXO
+	5,  // 6: spv.SPV.GetBestHeader:input_type -> spv.GetBestHeaderRequest
+	6,  // 7: spv.SPV.GetHeader:input_type -> spv.GetHeaderRequest
+	7,  // 8: spv.SPV.GetBlock:input_type -> spv.GetBlockRequest
+	8,  // 9: spv.SPV.GetTransaction:input_type -> spv.GetTransactionRequest
+	9,  // 10: spv.SPV.RegisterAddress:input_type -> spv.RegisterAddressRequest
+	11, // 11: spv.SPV.UnregisterAddress:input_type -> spv.UnregisterAddressRequest
+	13, // 12: spv.SPV.GetAddresses:input_type -> spv.GetAddressesRequest
+	17, // 13: spv.SPV.ListUnspent:input_type -> spv.ListUnspentRequest
+	19, // 14: spv.SPV.GetBalance:input_type -> spv.GetBalanceRequest
+	21, // 15: spv.SPV.SendRawTransaction:input_type -> spv.SendRawTransactionRequest
+	23, // 16: spv.SPV.SubscribeBlocks:input_type -> spv.SubscribeBlocksRequest
+	24, // 17: spv.SPV.SubscribeTransactions:input_type -> spv.SubscribeTransactionsRequest
+	0,  // 18: spv.SPV.GetBestHeader:output_type -> spv.Header
+	0,  // 19: spv.SPV.GetHeader:output_type -> spv.Header
+	4,  // 20: spv.SPV.GetBlock:output_type -> spv.Block
+	3,  // 21: spv.SPV.GetTransaction:output_type -> spv.Transaction
+	10, // 22: spv.SPV.RegisterAddress:output_type -> spv.RegisterAddressResponse
+	12, // 23: spv.SPV.UnregisterAddress:output_type -> spv.UnregisterAddressResponse
+	15, // 24: spv.SPV.GetAddresses:output_type -> spv.GetAddressesResponse
+	18, // 25: spv.SPV.ListUnspent:output_type -> spv.ListUnspentResponse
+	20, // 26: spv.SPV.GetBalance:output_type -> spv.GetBalanceResponse
+	22, // 27: spv.SPV.SendRawTransaction:output_type -> spv.SendRawTransactionResponse
+	0,  // 28: spv.SPV.SubscribeBlocks:output_type -> spv.Header
+	3,  // 29: spv.SPV.SubscribeTransactions:output_type -> spv.Transaction
+	18, // [18:30] is the sub-list for method output_type
+	6,  // [6:18] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_spv_proto_init() }
+func file_spv_proto_init() {
+	if File_spv_proto != nil {
+		return
+	}
+	file_spv_proto_msgTypes[6].OneofWrappers = []any{
+		(*GetHeaderRequest_Hash)(nil),
+		(*GetHeaderRequest_Height)(nil),
+	}
+	file_spv_proto_msgTypes[7].OneofWrappers = []any{
+		(*GetBlockRequest_Hash)(nil),
+		(*GetBlockRequest_Height)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_spv_proto_rawDesc), len(file_spv_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_spv_proto_goTypes,
+		DependencyIndexes: file_spv_proto_depIdxs,
+		MessageInfos:      file_spv_proto_msgTypes,
+	}.Build()
+	File_spv_proto = out.File
+	file_spv_proto_goTypes = nil
+	file_spv_proto_depIdxs = nil
+}
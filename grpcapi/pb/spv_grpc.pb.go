@@ -0,0 +1,564 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: spv.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SPV_GetBestHeader_FullMethodName         = "/spv.SPV/GetBestHeader"
+	SPV_GetHeader_FullMethodName             = "/spv.SPV/GetHeader"
+	SPV_GetBlock_FullMethodName              = "/spv.SPV/GetBlock"
+	SPV_GetTransaction_FullMethodName        = "/spv.SPV/GetTransaction"
+	SPV_RegisterAddress_FullMethodName       = "/spv.SPV/RegisterAddress"
+	SPV_UnregisterAddress_FullMethodName     = "/spv.SPV/UnregisterAddress"
+	SPV_GetAddresses_FullMethodName          = "/spv.SPV/GetAddresses"
+	SPV_ListUnspent_FullMethodName           = "/spv.SPV/ListUnspent"
+	SPV_GetBalance_FullMethodName            = "/spv.SPV/GetBalance"
+	SPV_SendRawTransaction_FullMethodName    = "/spv.SPV/SendRawTransaction"
+	SPV_SubscribeBlocks_FullMethodName       = "/spv.SPV/SubscribeBlocks"
+	SPV_SubscribeTransactions_FullMethodName = "/spv.SPV/SubscribeTransactions"
+)
+
+// SPVClient is the client API for SPV service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SPV is the gRPC API of the SPV node. Hashes and transaction ids are hex
+// strings in the same byte order as the JSON-RPC interfaces, amounts are
+// in sela (1 ELA = 100000000 sela).
+type SPVClient interface {
+	// Chain queries
+	GetBestHeader(ctx context.Context, in *GetBestHeaderRequest, opts ...grpc.CallOption) (*Header, error)
+	GetHeader(ctx context.Context, in *GetHeaderRequest, opts ...grpc.CallOption) (*Header, error)
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	// Address registration
+	RegisterAddress(ctx context.Context, in *RegisterAddressRequest, opts ...grpc.CallOption) (*RegisterAddressResponse, error)
+	UnregisterAddress(ctx context.Context, in *UnregisterAddressRequest, opts ...grpc.CallOption) (*UnregisterAddressResponse, error)
+	GetAddresses(ctx context.Context, in *GetAddressesRequest, opts ...grpc.CallOption) (*GetAddressesResponse, error)
+	// UTXO and balance queries
+	ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error)
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	// Broadcast
+	SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendRawTransactionResponse, error)
+	// Subscriptions
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Header], error)
+	SubscribeTransactions(ctx context.Context, in *SubscribeTransactionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Transaction], error)
+}
+
+type sPVClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSPVClient(cc grpc.ClientConnInterface) SPVClient {
+	return &sPVClient{cc}
+}
+
+func (c *sPVClient) GetBestHeader(ctx context.Context, in *GetBestHeaderRequest, opts ...grpc.CallOption) (*Header, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Header)
+	err := c.cc.Invoke(ctx, SPV_GetBestHeader_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) GetHeader(ctx context.Context, in *GetHeaderRequest, opts ...grpc.CallOption) (*Header, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Header)
+	err := c.cc.Invoke(ctx, SPV_GetHeader_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Block)
+	err := c.cc.Invoke(ctx, SPV_GetBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, SPV_GetTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) RegisterAddress(ctx context.Context, in *RegisterAddressRequest, opts ...grpc.CallOption) (*RegisterAddressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterAddressResponse)
+	err := c.cc.Invoke(ctx, SPV_RegisterAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) UnregisterAddress(ctx context.Context, in *UnregisterAddressRequest, opts ...grpc.CallOption) (*UnregisterAddressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnregisterAddressResponse)
+	err := c.cc.Invoke(ctx, SPV_UnregisterAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) GetAddresses(ctx context.Context, in *GetAddressesRequest, opts ...grpc.CallOption) (*GetAddressesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAddressesResponse)
+	err := c.cc.Invoke(ctx, SPV_GetAddresses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUnspentResponse)
+	err := c.cc.Invoke(ctx, SPV_ListUnspent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalanceResponse)
+	err := c.cc.Invoke(ctx, SPV_GetBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendRawTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendRawTransactionResponse)
+	err := c.cc.Invoke(ctx, SPV_SendRawTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sPVClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Header], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SPV_ServiceDesc.Streams[0], SPV_SubscribeBlocks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeBlocksRequest, Header]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SPV_SubscribeBlocksClient = grpc.ServerStreamingClient[Header]
+
+func (c *sPVClient) SubscribeTransactions(ctx context.Context, in *SubscribeTransactionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Transaction], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SPV_ServiceDesc.Streams[1], SPV_SubscribeTransactions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeTransactionsRequest, Transaction]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SPV_SubscribeTransactionsClient = grpc.ServerStreamingClient[Transaction]
+
+// SPVServer is the server API for SPV service.
+// All implementations must embed UnimplementedSPVServer
+// for forward compatibility.
+//
+// SPV is the gRPC API of the SPV node. Hashes and transaction ids are hex
+// strings in the same byte order as the JSON-RPC interfaces, amounts are
+// in sela (1 ELA = 100000000 sela).
+type SPVServer interface {
+	// Chain queries
+	GetBestHeader(context.Context, *GetBestHeaderRequest) (*Header, error)
+	GetHeader(context.Context, *GetHeaderRequest) (*Header, error)
+	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*Transaction, error)
+	// Address registration
+	RegisterAddress(context.Context, *RegisterAddressRequest) (*RegisterAddressResponse, error)
+	UnregisterAddress(context.Context, *UnregisterAddressRequest) (*UnregisterAddressResponse, error)
+	GetAddresses(context.Context, *GetAddressesRequest) (*GetAddressesResponse, error)
+	// UTXO and balance queries
+	ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error)
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	// Broadcast
+	SendRawTransaction(context.Context, *SendRawTransactionRequest) (*SendRawTransactionResponse, error)
+	// Subscriptions
+	SubscribeBlocks(*SubscribeBlocksRequest, grpc.ServerStreamingServer[Header]) error
+	SubscribeTransactions(*SubscribeTransactionsRequest, grpc.ServerStreamingServer[Transaction]) error
+	mustEmbedUnimplementedSPVServer()
+}
+
+// UnimplementedSPVServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSPVServer struct{}
+
+func (UnimplementedSPVServer) GetBestHeader(context.Context, *GetBestHeaderRequest) (*Header, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBestHeader not implemented")
+}
+func (UnimplementedSPVServer) GetHeader(context.Context, *GetHeaderRequest) (*Header, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeader not implemented")
+}
+func (UnimplementedSPVServer) GetBlock(context.Context, *GetBlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedSPVServer) GetTransaction(context.Context, *GetTransactionRequest) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedSPVServer) RegisterAddress(context.Context, *RegisterAddressRequest) (*RegisterAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterAddress not implemented")
+}
+func (UnimplementedSPVServer) UnregisterAddress(context.Context, *UnregisterAddressRequest) (*UnregisterAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterAddress not implemented")
+}
+func (UnimplementedSPVServer) GetAddresses(context.Context, *GetAddressesRequest) (*GetAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAddresses not implemented")
+}
+func (UnimplementedSPVServer) ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUnspent not implemented")
+}
+func (UnimplementedSPVServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedSPVServer) SendRawTransaction(context.Context, *SendRawTransactionRequest) (*SendRawTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendRawTransaction not implemented")
+}
+func (UnimplementedSPVServer) SubscribeBlocks(*SubscribeBlocksRequest, grpc.ServerStreamingServer[Header]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+func (UnimplementedSPVServer) SubscribeTransactions(*SubscribeTransactionsRequest, grpc.ServerStreamingServer[Transaction]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeTransactions not implemented")
+}
+func (UnimplementedSPVServer) mustEmbedUnimplementedSPVServer() {}
+func (UnimplementedSPVServer) testEmbeddedByValue()             {}
+
+// UnsafeSPVServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SPVServer will
+// result in compilation errors.
+type UnsafeSPVServer interface {
+	mustEmbedUnimplementedSPVServer()
+}
+
+func RegisterSPVServer(s grpc.ServiceRegistrar, srv SPVServer) {
+	// If the following call pancis, it indicates UnimplementedSPVServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SPV_ServiceDesc, srv)
+}
+
+func _SPV_GetBestHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBestHeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).GetBestHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_GetBestHeader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).GetBestHeader(ctx, req.(*GetBestHeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_GetHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).GetHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_GetHeader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).GetHeader(ctx, req.(*GetHeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_GetBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_GetTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_RegisterAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).RegisterAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_RegisterAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).RegisterAddress(ctx, req.(*RegisterAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_UnregisterAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).UnregisterAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_UnregisterAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).UnregisterAddress(ctx, req.(*UnregisterAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_GetAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).GetAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_GetAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).GetAddresses(ctx, req.(*GetAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnspentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).ListUnspent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_ListUnspent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).ListUnspent(ctx, req.(*ListUnspentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_GetBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_SendRawTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRawTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SPVServer).SendRawTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SPV_SendRawTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SPVServer).SendRawTransaction(ctx, req.(*SendRawTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SPV_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SPVServer).SubscribeBlocks(m, &grpc.GenericServerStream[SubscribeBlocksRequest, Header]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SPV_SubscribeBlocksServer = grpc.ServerStreamingServer[Header]
+
+func _SPV_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTransactionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SPVServer).SubscribeTransactions(m, &grpc.GenericServerStream[SubscribeTransactionsRequest, Transaction]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SPV_SubscribeTransactionsServer = grpc.ServerStreamingServer[Transaction]
+
+// SPV_ServiceDesc is the grpc.ServiceDesc for SPV service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SPV_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "spv.SPV",
+	HandlerType: (*SPVServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBestHeader",
+			Handler:    _SPV_GetBestHeader_Handler,
+		},
+		{
+			MethodName: "GetHeader",
+			Handler:    _SPV_GetHeader_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _SPV_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _SPV_GetTransaction_Handler,
+		},
+		{
+			MethodName: "RegisterAddress",
+			Handler:    _SPV_RegisterAddress_Handler,
+		},
+		{
+			MethodName: "UnregisterAddress",
+			Handler:    _SPV_UnregisterAddress_Handler,
+		},
+		{
+			MethodName: "GetAddresses",
+			Handler:    _SPV_GetAddresses_Handler,
+		},
+		{
+			MethodName: "ListUnspent",
+			Handler:    _SPV_ListUnspent_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _SPV_GetBalance_Handler,
+		},
+		{
+			MethodName: "SendRawTransaction",
+			Handler:    _SPV_SendRawTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _SPV_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeTransactions",
+			Handler:       _SPV_SubscribeTransactions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "spv.proto",
+}
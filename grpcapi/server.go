@@ -0,0 +1,512 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+	"github.com/elastos/Elastos.ELA.SPV.Node/grpcapi/pb"
+	"github.com/elastos/Elastos.ELA.SPV.Node/node"
+	"github.com/elastos/Elastos.ELA.SPV.Node/rpc"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+	"github.com/elastos/Elastos.ELA.SPV/sdk"
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// subscriberBuffer is the number of messages buffered for a subscriber,
+// a subscriber too slow to receive them will be closed.
+const subscriberBuffer = 256
+
+type subscriber struct {
+	blocks  bool
+	addrs   map[common.Uint168]struct{}
+	send    chan interface{}
+	overrun chan struct{}
+}
+
+// Server implements the SPV gRPC service over the SPV node.
+type Server struct {
+	pb.UnimplementedSPVServer
+	node *node.SPVNode
+
+	sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewServer(spvNode *node.SPVNode) *Server {
+	server := &Server{
+		node:        spvNode,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+	spvNode.AddListener(server)
+	return server
+}
+
+// StartServer serves the gRPC API on the GRPCPort, it does nothing if the
+// port is not specified.
+func StartServer(spvNode *node.SPVNode) {
+	port := config.Values().GRPCPort
+	if port == 0 {
+		return
+	}
+
+	log.Debug("Start gRPC server at port:", port)
+	listener, err := net.Listen("tcp", net.JoinHostPort(config.Values().RPCBind, strconv.Itoa(port)))
+	if err != nil {
+		log.Error("gRPC server listen error: ", err.Error())
+		return
+	}
+
+	// Share the credentials and certificate with the RPC server
+	tlsConfig, err := rpc.TLSConfig()
+	if err != nil {
+		log.Error("gRPC server TLS config error: ", err.Error())
+		return
+	}
+	authorizer := rpc.NewAuthorizer(config.Values().RPCUsers)
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryAuth(authorizer)),
+		grpc.StreamInterceptor(streamAuth(authorizer)),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(options...)
+	pb.RegisterSPVServer(server, NewServer(spvNode))
+	if err := server.Serve(listener); err != nil {
+		log.Error("gRPC server serve error: ", err.Error())
+	}
+}
+
+func (s *Server) GetBestHeader(ctx context.Context, req *pb.GetBestHeaderRequest) (*pb.Header, error) {
+	tip, err := s.node.GetBestHeader()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "query best header failed %s", err.Error())
+	}
+	return s.toHeader(&tip.Header), nil
+}
+
+func (s *Server) GetHeader(ctx context.Context, req *pb.GetHeaderRequest) (*pb.Header, error) {
+	var hash *common.Uint256
+	var err error
+	switch block := req.Block.(type) {
+	case *pb.GetHeaderRequest_Hash:
+		hash, err = s.parseHash(block.Hash)
+	case *pb.GetHeaderRequest_Height:
+		hash, err = s.getHeaderHash(block.Height)
+	default:
+		err = status.Error(codes.InvalidArgument, "block hash or height not specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := s.getHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+	return s.toHeader(header), nil
+}
+
+func (s *Server) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb.Block, error) {
+	var hash *common.Uint256
+	var err error
+	switch block := req.Block.(type) {
+	case *pb.GetBlockRequest_Hash:
+		hash, err = s.parseHash(block.Hash)
+	case *pb.GetBlockRequest_Height:
+		hash, err = s.getHeaderHash(block.Height)
+	default:
+		err = status.Error(codes.InvalidArgument, "block hash or height not specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := s.getHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+	txIds, err := s.node.GetTxIds(header.Height)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "query block transactions failed %s", err.Error())
+	}
+
+	block := &pb.Block{Header: s.toHeader(header)}
+	for _, txId := range txIds {
+		tx, err := s.node.GetTx(txId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "query transaction %s failed %s",
+				hashString(*txId), err.Error())
+		}
+		block.Transactions = append(block.Transactions, s.toTransaction(&tx.Transaction, header))
+	}
+	return block, nil
+}
+
+func (s *Server) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.Transaction, error) {
+	txId, err := s.parseHash(req.Txid)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.node.GetTx(txId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "unknown transaction %s", req.Txid)
+	}
+
+	hash, err := s.getHeaderHash(tx.Height)
+	if err != nil {
+		return nil, err
+	}
+	header, err := s.getHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+	return s.toTransaction(&tx.Transaction, header), nil
+}
+
+func (s *Server) RegisterAddress(ctx context.Context, req *pb.RegisterAddressRequest) (*pb.RegisterAddressResponse, error) {
+	if _, err := common.Uint168FromAddress(req.Address); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+	}
+	if err := s.node.RegisterAddress(req.Address, req.Label, req.Birthday); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "register address %s error %s",
+			req.Address, err.Error())
+	}
+	return &pb.RegisterAddressResponse{}, nil
+}
+
+func (s *Server) UnregisterAddress(ctx context.Context, req *pb.UnregisterAddressRequest) (*pb.UnregisterAddressResponse, error) {
+	if err := s.node.UnregisterAddress(req.Address, req.Purge); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unregister address %s error %s",
+			req.Address, err.Error())
+	}
+	return &pb.UnregisterAddressResponse{}, nil
+}
+
+func (s *Server) GetAddresses(ctx context.Context, req *pb.GetAddressesRequest) (*pb.GetAddressesResponse, error) {
+	addrs, err := s.node.GetStoreAddrs()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "query addresses failed %s", err.Error())
+	}
+
+	resp := &pb.GetAddressesResponse{}
+	for _, addr := range addrs {
+		resp.Addresses = append(resp.Addresses, &pb.Address{
+			Address:        addr.Address,
+			Label:          addr.Label,
+			RegisterTime:   addr.Time,
+			RegisterHeight: addr.Height,
+			Birthday:       addr.Birthday,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) ListUnspent(ctx context.Context, req *pb.ListUnspentRequest) (*pb.ListUnspentResponse, error) {
+	addrs, err := s.parseAddresses(req.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	var assetId *common.Uint256
+	if len(req.AssetId) > 0 {
+		if assetId, err = s.parseHash(req.AssetId); err != nil {
+			return nil, err
+		}
+	}
+	maxConf := req.MaxConf
+	if maxConf == 0 {
+		maxConf = math.MaxUint32
+	}
+
+	utxos, err := s.node.GetUTXOs()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "query unspent outputs failed %s", err.Error())
+	}
+
+	resp := &pb.ListUnspentResponse{}
+	for _, utxo := range utxos {
+		if addrs != nil {
+			if _, ok := addrs[utxo.ProgramHash]; !ok {
+				continue
+			}
+		}
+		if assetId != nil && utxo.AssetID != *assetId {
+			continue
+		}
+		confirmations := s.getConfirmations(utxo.Height)
+		if confirmations < req.MinConf || confirmations > maxConf {
+			continue
+		}
+
+		address, _ := utxo.ProgramHash.ToAddress()
+		resp.Utxos = append(resp.Utxos, &pb.UTXO{
+			Txid:          hashString(utxo.Op.TxID),
+			Vout:          uint32(utxo.Op.Index),
+			Address:       address,
+			Value:         int64(utxo.Value),
+			AssetId:       hashString(utxo.AssetID),
+			OutputLock:    utxo.OutputLock,
+			Height:        utxo.Height,
+			Confirmations: confirmations,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) GetBalance(ctx context.Context, req *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	var programHash *common.Uint168
+	if len(req.Address) > 0 {
+		var err error
+		programHash, err = common.Uint168FromAddress(req.Address)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", req.Address)
+		}
+	}
+	assetId := node.AssetEla
+	if len(req.AssetId) > 0 {
+		id, err := s.parseHash(req.AssetId)
+		if err != nil {
+			return nil, err
+		}
+		assetId = *id
+	}
+
+	utxos, err := s.node.GetUTXOs()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "query unspent outputs failed %s", err.Error())
+	}
+
+	var balance common.Fixed64
+	for _, utxo := range utxos {
+		if programHash != nil && utxo.ProgramHash != *programHash {
+			continue
+		}
+		if utxo.AssetID != assetId || s.getConfirmations(utxo.Height) < req.MinConf {
+			continue
+		}
+		balance += utxo.Value
+	}
+	return &pb.GetBalanceResponse{Balance: int64(balance)}, nil
+}
+
+func (s *Server) SendRawTransaction(ctx context.Context, req *pb.SendRawTransactionRequest) (*pb.SendRawTransactionResponse, error) {
+	var tx core.Transaction
+	if err := tx.Deserialize(bytes.NewReader(req.Raw)); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction deserialize failed %s", err.Error())
+	}
+	txId, err := s.node.SendTransaction(tx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "send transaction failed %s", err.Error())
+	}
+	return &pb.SendRawTransactionResponse{Txid: hashString(*txId)}, nil
+}
+
+func (s *Server) SubscribeBlocks(req *pb.SubscribeBlocksRequest, stream pb.SPV_SubscribeBlocksServer) error {
+	return s.subscribe(&subscriber{blocks: true}, stream.Context(), func(msg interface{}) error {
+		return stream.Send(msg.(*pb.Header))
+	})
+}
+
+func (s *Server) SubscribeTransactions(req *pb.SubscribeTransactionsRequest, stream pb.SPV_SubscribeTransactionsServer) error {
+	addrs, err := s.parseAddresses(req.Addresses)
+	if err != nil {
+		return err
+	}
+	return s.subscribe(&subscriber{addrs: addrs}, stream.Context(), func(msg interface{}) error {
+		return stream.Send(msg.(*pb.Transaction))
+	})
+}
+
+// subscribe sends the messages to the subscriber until the stream closed.
+func (s *Server) subscribe(sub *subscriber, ctx context.Context, send func(interface{}) error) error {
+	sub.send = make(chan interface{}, subscriberBuffer)
+	sub.overrun = make(chan struct{})
+
+	s.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.Unlock()
+
+	defer func() {
+		s.Lock()
+		delete(s.subscribers, sub)
+		s.Unlock()
+	}()
+
+	for {
+		select {
+		case msg := <-sub.send:
+			if err := send(msg); err != nil {
+				return err
+			}
+		case <-sub.overrun:
+			return status.Error(codes.ResourceExhausted, "subscriber too slow to receive messages")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// OnBlock implements node.Listener, sends the block and the transactions
+// to subscribers.
+func (s *Server) OnBlock(header *core.Header, txs []*core.Transaction, rescanned bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	var msg *pb.Header
+	for sub := range s.subscribers {
+		if sub.blocks {
+			// Blocks synchronized again by rescan have been sent
+			if rescanned {
+				continue
+			}
+			if msg == nil {
+				msg = s.toHeader(header)
+			}
+			s.push(sub, msg)
+			continue
+		}
+		for _, tx := range txs {
+			if sub.addrs == nil || s.node.TxMatches(tx, sub.addrs) {
+				s.push(sub, s.toTransaction(tx, header))
+			}
+		}
+	}
+}
+
+func (s *Server) OnRollback(height uint32, txs []*core.Transaction) {}
+
+func (s *Server) OnStateChange(state sdk.ChainState) {}
+
+func (s *Server) push(sub *subscriber, msg interface{}) {
+	select {
+	case sub.send <- msg:
+	case <-sub.overrun:
+	default:
+		log.Warn("gRPC subscriber send buffer full, closing")
+		close(sub.overrun)
+	}
+}
+
+func (s *Server) toHeader(header *core.Header) *pb.Header {
+	buf := new(bytes.Buffer)
+	header.Serialize(buf)
+	hash := header.Hash()
+	return &pb.Header{
+		Hash:              hashString(hash),
+		Height:            header.Height,
+		Version:           header.Version,
+		PreviousBlockHash: hashString(header.Previous),
+		MerkleRoot:        hashString(header.MerkleRoot),
+		Time:              header.Timestamp,
+		Bits:              header.Bits,
+		Nonce:             header.Nonce,
+		Confirmations:     s.getConfirmations(header.Height),
+		Raw:               buf.Bytes(),
+	}
+}
+
+func (s *Server) toTransaction(tx *core.Transaction, header *core.Header) *pb.Transaction {
+	buf := new(bytes.Buffer)
+	tx.Serialize(buf)
+	txId := tx.Hash()
+	msg := &pb.Transaction{
+		Txid:           hashString(txId),
+		Type:           uint32(tx.TxType),
+		PayloadVersion: uint32(tx.PayloadVersion),
+		LockTime:       tx.LockTime,
+		Raw:            buf.Bytes(),
+	}
+	for _, input := range tx.Inputs {
+		msg.Inputs = append(msg.Inputs, &pb.Input{
+			Txid:     hashString(input.Previous.TxID),
+			Vout:     uint32(input.Previous.Index),
+			Sequence: input.Sequence,
+		})
+	}
+	for i, output := range tx.Outputs {
+		address, _ := output.ProgramHash.ToAddress()
+		msg.Outputs = append(msg.Outputs, &pb.Output{
+			Index:      uint32(i),
+			Value:      int64(output.Value),
+			Address:    address,
+			AssetId:    hashString(output.AssetID),
+			OutputLock: output.OutputLock,
+		})
+	}
+	if header != nil {
+		msg.BlockHash = hashString(header.Hash())
+		msg.Height = header.Height
+		msg.Confirmations = s.getConfirmations(header.Height)
+	}
+	return msg
+}
+
+func (s *Server) getHeaderHash(height uint32) (*common.Uint256, error) {
+	hash, err := s.node.GetHeaderHash(height)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "unknown block at height %d", height)
+	}
+	return hash, nil
+}
+
+func (s *Server) getHeader(hash *common.Uint256) (*core.Header, error) {
+	header, err := s.node.GetHeader(hash)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "unknown block with hash %s", hashString(*hash))
+	}
+	return &header.Header, nil
+}
+
+func (s *Server) getConfirmations(height uint32) uint32 {
+	best := s.node.BestHeight()
+	if height > best {
+		return 0
+	}
+	return best - height + 1
+}
+
+// hashString encodes the hash in the same format as parseHash accepts.
+func hashString(hash common.Uint256) string {
+	return common.BytesToHexString(hash.Bytes())
+}
+
+func (s *Server) parseHash(hex string) (*common.Uint256, error) {
+	data, err := common.HexStringToBytes(hex)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid hash %s", hex)
+	}
+	hash, err := common.Uint256FromBytes(data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid hash %s", hex)
+	}
+	return hash, nil
+}
+
+// parseAddresses returns nil if no address given, which means all
+// registered addresses.
+func (s *Server) parseAddresses(addresses []string) (map[common.Uint168]struct{}, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	addrs := make(map[common.Uint168]struct{})
+	for _, address := range addresses {
+		hash, err := common.Uint168FromAddress(address)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid address %s", address)
+		}
+		addrs[*hash] = struct{}{}
+	}
+	return addrs, nil
+}
@@ -3,12 +3,15 @@ package main
 import (
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/elastos/Elastos.ELA.SPV/log"
 
 	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+	"github.com/elastos/Elastos.ELA.SPV.Node/grpcapi"
 	"github.com/elastos/Elastos.ELA.SPV.Node/node"
 	"github.com/elastos/Elastos.ELA.SPV.Node/rpc"
+	"github.com/elastos/Elastos.ELA.SPV.Node/webhook"
 )
 
 func main() {
@@ -20,19 +23,38 @@ func main() {
 		os.Exit(1)
 	}
 
+	webhooks, err := webhook.New(spvNode, config.Values().Webhooks)
+	if err != nil {
+		log.Error("Webhooks initialize failed, ", err)
+		os.Exit(1)
+	}
+	webhooks.Start()
+
 	// Handle interrupt signal
 	stop := make(chan int, 1)
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	go func() {
-		for range c {
-			log.Trace("SPV node shutting down...")
-			spvNode.Stop()
-			stop <- 1
+		<-c
+		log.Trace("SPV node shutting down...")
+		webhooks.Stop()
+		spvNode.Stop()
+		stop <- 1
+	}()
+
+	// Reload RPC server certificate on hangup signal
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := rpc.ReloadCertificate(); err != nil {
+				log.Error("Reload RPC server certificate failed, ", err)
+			}
 		}
 	}()
 
 	go rpc.StartServer(spvNode)
+	go grpcapi.StartServer(spvNode)
 	spvNode.Start()
 
 	<-stop
@@ -0,0 +1,49 @@
+package node
+
+import (
+	"encoding/binary"
+
+	"github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+// AddrTxCursorSize is the size of the cursor of an address history entry,
+// which is the height followed by the transaction ID.
+const AddrTxCursorSize = 4 + len(common.Uint256{})
+
+// AddrTx is an entry in the transaction history of a registered address,
+// with the amounts the transaction received to and sent from the address.
+type AddrTx struct {
+	TxID     common.Uint256
+	Height   uint32
+	Received map[common.Uint256]common.Fixed64
+	Sent     map[common.Uint256]common.Fixed64
+}
+
+func NewAddrTx(txId common.Uint256, height uint32) *AddrTx {
+	return &AddrTx{
+		TxID:     txId,
+		Height:   height,
+		Received: make(map[common.Uint256]common.Fixed64),
+		Sent:     make(map[common.Uint256]common.Fixed64),
+	}
+}
+
+// Delta returns the balance change of the address in the given asset.
+func (a *AddrTx) Delta(assetId common.Uint256) common.Fixed64 {
+	return a.Received[assetId] - a.Sent[assetId]
+}
+
+// Cursor returns the position of this entry in the address history, it can
+// be used to continue the history query right after this entry.
+func (a *AddrTx) Cursor() []byte {
+	cursor := make([]byte, AddrTxCursorSize)
+	binary.BigEndian.PutUint32(cursor[:4], a.Height)
+	copy(cursor[4:], a.TxID[:])
+	return cursor
+}
+
+func addrTxKey(hash *common.Uint168, cursor []byte) []byte {
+	key := make([]byte, 0, len(hash)+len(cursor))
+	key = append(key, hash[:]...)
+	return append(key, cursor...)
+}
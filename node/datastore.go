@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/gob"
+	"fmt"
 	"github.com/boltdb/bolt"
 	"sync"
 
@@ -17,12 +18,22 @@ var (
 	BKTTxs       = []byte("Txs")
 	BKTHeightTxs = []byte("HeightTxs")
 	BKTOps       = []byte("Ops")
+	BKTSpends    = []byte("Spends")
+	BKTAddrTxs   = []byte("AddrTxs")
+	BKTProofs    = []byte("MerkleProofs")
+	BKTMeta      = []byte("Meta")
+	KEYVersion   = []byte("Version")
 )
 
+// dataStoreVersion is the layout version of the database, indexes added by
+// later versions are built from the stored transactions on open.
+const dataStoreVersion = 2
+
 type DataStore struct {
 	*sync.RWMutex
 	*bolt.DB
-	filter *sdk.AddrFilter
+	filter    *sdk.AddrFilter
+	birthdays map[common.Uint168]uint32
 }
 
 func NewDataStore() (*DataStore, error) {
@@ -51,19 +62,102 @@ func NewDataStore() (*DataStore, error) {
 		if err != nil {
 			return err
 		}
+		_, err = btx.CreateBucketIfNotExists(BKTSpends)
+		if err != nil {
+			return err
+		}
+		_, err = btx.CreateBucketIfNotExists(BKTAddrTxs)
+		if err != nil {
+			return err
+		}
+		_, err = btx.CreateBucketIfNotExists(BKTProofs)
+		if err != nil {
+			return err
+		}
+		_, err = btx.CreateBucketIfNotExists(BKTMeta)
+		if err != nil {
+			return err
+		}
 		return nil
 	})
 
-	addrs, err := store.getAddrs()
+	storeAddrs, err := store.getStoreAddrs()
 	if err != nil {
 		return nil, err
 	}
+	addrs := make([]*common.Uint168, 0, len(storeAddrs))
+	store.birthdays = make(map[common.Uint168]uint32)
+	for _, addr := range storeAddrs {
+		hash := addr.Hash
+		addrs = append(addrs, &hash)
+		store.birthdays[hash] = addr.Birthday
+	}
 	store.filter = sdk.NewAddrFilter(addrs)
 
+	if err := store.migrate(); err != nil {
+		return nil, err
+	}
+
 	return store, nil
 }
 
-func (t *DataStore) PutAddr(address string) (bool, error) {
+// migrate builds the indexes missing in databases created by earlier
+// versions, it only runs once as the version is recorded after.
+func (t *DataStore) migrate() error {
+	return t.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(BKTMeta)
+		var version uint32
+		if data := meta.Get(KEYVersion); len(data) == 4 {
+			version = binary.LittleEndian.Uint32(data)
+		}
+		if version >= dataStoreVersion {
+			return nil
+		}
+
+		if version < 1 {
+			if err := backfillSpends(tx); err != nil {
+				return err
+			}
+		}
+		if version < 2 {
+			if err := t.backfillAddrTxs(tx); err != nil {
+				return err
+			}
+		}
+
+		var data [4]byte
+		binary.LittleEndian.PutUint32(data[:], dataStoreVersion)
+		return meta.Put(KEYVersion, data[:])
+	})
+}
+
+func (t *DataStore) PutAddr(addr *StoreAddr) (bool, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.filter.ContainAddr(addr.Hash) {
+		return false, nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := addr.Serialize(buf); err != nil {
+		return false, err
+	}
+
+	hash := addr.Hash
+	t.filter.AddAddr(&hash)
+	t.birthdays[hash] = addr.Birthday
+	return true, t.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTAddrs).Put([]byte(addr.Address), buf.Bytes())
+	})
+}
+
+// DeleteAddr removes the address from the registered addresses, if purge is
+// set, the transactions and outpoints belongs to the address will also be
+// removed, except those transactions still corresponding with other addresses.
+// Otherwise the outpoints are kept, so the spends of them are still tracked
+// and they are still counted in the balance.
+func (t *DataStore) DeleteAddr(address string, purge bool) (bool, error) {
 	t.Lock()
 	defer t.Unlock()
 
@@ -72,14 +166,154 @@ func (t *DataStore) PutAddr(address string) (bool, error) {
 		return false, err
 	}
 
-	if t.filter.ContainAddr(*hash) {
+	if !t.filter.ContainAddr(*hash) {
 		return false, nil
 	}
 
-	t.filter.AddAddr(hash)
-	return true, t.Update(func(tx *bolt.Tx) error {
-		return tx.Bucket(BKTAddrs).Put([]byte(address), hash.Bytes())
+	return true, t.deleteAddrs(map[common.Uint168]string{*hash: address}, purge)
+}
+
+// DeleteAddrs removes the addresses like DeleteAddr in one database
+// transaction, nothing is removed if any of them has not registered.
+func (t *DataStore) DeleteAddrs(addresses []string, purge bool) error {
+	t.Lock()
+	defer t.Unlock()
+
+	hashes := make(map[common.Uint168]string)
+	for _, address := range addresses {
+		hash, err := common.Uint168FromAddress(address)
+		if err != nil {
+			return err
+		}
+		if !t.filter.ContainAddr(*hash) {
+			return fmt.Errorf("address %s has not registered", address)
+		}
+		hashes[*hash] = address
+	}
+
+	return t.deleteAddrs(hashes, purge)
+}
+
+func (t *DataStore) deleteAddrs(hashes map[common.Uint168]string, purge bool) error {
+	addrs := make([]*common.Uint168, 0)
+	for _, addr := range t.filter.GetAddrs() {
+		if _, ok := hashes[*addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	filter := sdk.NewAddrFilter(addrs)
+
+	err := t.Update(func(tx *bolt.Tx) error {
+		txIds := make(map[common.Uint256]uint32)
+		for hash, address := range hashes {
+			if err := deleteAddr(tx, hash, address, purge, txIds); err != nil {
+				return err
+			}
+		}
+		// Remove transactions only after the outpoints of all the addresses
+		// removed, they may still be referenced by the outpoints
+		return deleteUnmatchedTxs(tx, filter, txIds)
+	})
+	if err != nil {
+		return err
+	}
+
+	t.filter = filter
+	for hash := range hashes {
+		delete(t.birthdays, hash)
+	}
+	return nil
+}
+
+// deleteAddr removes the address, and it's history and outpoints if purge is
+// set, the transactions in the history are collected into txIds.
+func deleteAddr(tx *bolt.Tx, hash common.Uint168, address string, purge bool,
+	txIds map[common.Uint256]uint32) error {
+	if err := tx.Bucket(BKTAddrs).Delete([]byte(address)); err != nil {
+		return err
+	}
+
+	if !purge {
+		return nil
+	}
+
+	// Remove address history
+	var keys [][]byte
+	prefix := hash.Bytes()
+	c := tx.Bucket(BKTAddrTxs).Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		addrTx, err := addrTxFromKeyValue(k[len(prefix):], v)
+		if err != nil {
+			return err
+		}
+		txIds[addrTx.TxID] = addrTx.Height
+		keys = append(keys, k)
+	}
+	for _, key := range keys {
+		if err := tx.Bucket(BKTAddrTxs).Delete(key); err != nil {
+			return err
+		}
+	}
+
+	// Remove outpoints
+	var ops [][]byte
+	err := tx.Bucket(BKTOps).ForEach(func(k, v []byte) error {
+		op, err := core.OutPointFromBytes(v)
+		if err != nil {
+			return err
+		}
+		utxo, err := getUTXO(tx, op)
+		if err != nil {
+			return err
+		}
+		if utxo.ProgramHash == hash {
+			ops = append(ops, k)
+		}
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		tx.Bucket(BKTOps).Delete(op)
+		tx.Bucket(BKTSpends).Delete(op)
+	}
+	return nil
+}
+
+// deleteUnmatchedTxs removes the transactions no longer corresponding with
+// any address in the filter.
+func deleteUnmatchedTxs(tx *bolt.Tx, filter *sdk.AddrFilter, txIds map[common.Uint256]uint32) error {
+	for txId, height := range txIds {
+		data := tx.Bucket(BKTTxs).Get(txId.Bytes())
+		if data == nil {
+			continue
+		}
+		var txn StoreTx
+		if err := txn.Deserialize(bytes.NewReader(data)); err != nil {
+			return err
+		}
+		if txnMatches(tx, filter, &txn) {
+			continue
+		}
+		if err := tx.Bucket(BKTTxs).Delete(txId.Bytes()); err != nil {
+			return err
+		}
+		if err := tx.Bucket(BKTProofs).Delete(txId.Bytes()); err != nil {
+			return err
+		}
+		if err := deleteHeightTx(tx, height, txId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *DataStore) ContainAddr(hash *common.Uint168) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.filter.ContainAddr(*hash)
 }
 
 func (t *DataStore) GetAddrs() []*common.Uint168 {
@@ -89,11 +323,40 @@ func (t *DataStore) GetAddrs() []*common.Uint168 {
 	return t.filter.GetAddrs()
 }
 
-func (t *DataStore) getAddrs() (addrs []*common.Uint168, err error) {
+// GetBornAddrs returns the registered addresses with birthday not later than
+// the given height.
+func (t *DataStore) GetBornAddrs(height uint32) []*common.Uint168 {
+	t.RLock()
+	defer t.RUnlock()
+
+	addrs := make([]*common.Uint168, 0)
+	for _, addr := range t.filter.GetAddrs() {
+		if t.birthdays[*addr] <= height {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func (t *DataStore) GetBirthday(hash *common.Uint168) uint32 {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.birthdays[*hash]
+}
+
+func (t *DataStore) GetStoreAddrs() ([]*StoreAddr, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.getStoreAddrs()
+}
+
+func (t *DataStore) getStoreAddrs() (addrs []*StoreAddr, err error) {
 	err = t.View(func(tx *bolt.Tx) error {
 		return tx.Bucket(BKTAddrs).ForEach(func(k, v []byte) error {
-			addr, err := common.Uint168FromBytes(v)
-			if err != nil {
+			addr := &StoreAddr{Address: string(k)}
+			if err := addr.Deserialize(v); err != nil {
 				return err
 			}
 			addrs = append(addrs, addr)
@@ -120,9 +383,17 @@ func (t *DataStore) PutTx(txn *StoreTx) (fPositive bool, err error) {
 			}
 		}
 
-		for _, input := range txn.Inputs {
+		for index, input := range txn.Inputs {
 			outpoint := tx.Bucket(BKTOps).Get(input.Previous.Bytes())
 			if outpoint != nil {
+				buf := new(bytes.Buffer)
+				spend := NewSpend(txn.Hash(), uint16(index), txn.Height)
+				if err := spend.Serialize(buf); err != nil {
+					return err
+				}
+				if err := tx.Bucket(BKTSpends).Put(outpoint, buf.Bytes()); err != nil {
+					return err
+				}
 				hits++
 			}
 		}
@@ -131,6 +402,10 @@ func (t *DataStore) PutTx(txn *StoreTx) (fPositive bool, err error) {
 			return nil
 		}
 
+		if err := t.putAddrTxs(tx, txn); err != nil {
+			return err
+		}
+
 		buf := new(bytes.Buffer)
 		if err = txn.Serialize(buf); err != nil {
 			return err
@@ -160,6 +435,19 @@ func (t *DataStore) PutTx(txn *StoreTx) (fPositive bool, err error) {
 	return hits == 0, err
 }
 
+// MatchesFilter checks if the transaction pays to a registered address or
+// spends a stored outpoint.
+func (t *DataStore) MatchesFilter(txn *core.Transaction) (matches bool) {
+	t.RLock()
+	defer t.RUnlock()
+
+	t.View(func(tx *bolt.Tx) error {
+		matches = txnMatches(tx, t.filter, NewStoreTx(txn, 0))
+		return nil
+	})
+	return matches
+}
+
 func (t *DataStore) GetTx(hash *common.Uint256) (txn *StoreTx, err error) {
 	t.RLock()
 	defer t.RUnlock()
@@ -173,6 +461,18 @@ func (t *DataStore) GetTx(hash *common.Uint256) (txn *StoreTx, err error) {
 	return txn, err
 }
 
+func (t *DataStore) HasTx(hash common.Uint256) (exist bool) {
+	t.RLock()
+	defer t.RUnlock()
+
+	t.View(func(tx *bolt.Tx) error {
+		exist = tx.Bucket(BKTTxs).Get(hash.Bytes()) != nil
+		return nil
+	})
+
+	return exist
+}
+
 func (t *DataStore) GetTxIds(height uint32) (txIds []*common.Uint256, err error) {
 	t.RLock()
 	defer t.RUnlock()
@@ -181,6 +481,10 @@ func (t *DataStore) GetTxIds(height uint32) (txIds []*common.Uint256, err error)
 		var key [4]byte
 		binary.LittleEndian.PutUint32(key[:], height)
 		data := tx.Bucket(BKTHeightTxs).Get(key[:])
+		// No transaction stored on this height
+		if data == nil {
+			return nil
+		}
 
 		var txMap = make(map[common.Uint256]uint32)
 		err = gob.NewDecoder(bytes.NewReader(data)).Decode(&txMap)
@@ -200,6 +504,45 @@ func (t *DataStore) GetTxIds(height uint32) (txIds []*common.Uint256, err error)
 	return txIds, err
 }
 
+// PutMerkleProofs stores the merkle proofs of transactions have been stored,
+// others are ignored.
+func (t *DataStore) PutMerkleProofs(proofs []*MerkleProof) error {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.Update(func(tx *bolt.Tx) error {
+		for _, proof := range proofs {
+			if tx.Bucket(BKTTxs).Get(proof.TxID.Bytes()) == nil {
+				continue
+			}
+			buf := new(bytes.Buffer)
+			if err := proof.Serialize(buf); err != nil {
+				return err
+			}
+			if err := tx.Bucket(BKTProofs).Put(proof.TxID.Bytes(), buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (t *DataStore) GetMerkleProof(hash *common.Uint256) (proof *MerkleProof, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	err = t.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(BKTProofs).Get(hash.Bytes())
+		if data == nil {
+			return fmt.Errorf("merkle proof of transaction %s does not exist in database", hash.String())
+		}
+		proof = new(MerkleProof)
+		return proof.Deserialize(bytes.NewReader(data))
+	})
+
+	return proof, err
+}
+
 func (t *DataStore) GetOps() (ops []*core.OutPoint, err error) {
 	t.RLock()
 	defer t.RUnlock()
@@ -218,6 +561,163 @@ func (t *DataStore) GetOps() (ops []*core.OutPoint, err error) {
 	return ops, err
 }
 
+func (t *DataStore) GetUTXOs() (utxos []*UTXO, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	err = t.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTOps).ForEach(func(k, v []byte) error {
+			if tx.Bucket(BKTSpends).Get(k) != nil {
+				return nil
+			}
+			op, err := core.OutPointFromBytes(v)
+			if err != nil {
+				return err
+			}
+			utxo, err := getUTXO(tx, op)
+			if err != nil {
+				return err
+			}
+			utxos = append(utxos, utxo)
+			return nil
+		})
+	})
+
+	return utxos, err
+}
+
+// GetOutputs returns all outputs ever paid to the registered addresses,
+// including those have been spent.
+func (t *DataStore) GetOutputs() (outputs []*UTXO, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	err = t.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTOps).ForEach(func(k, v []byte) error {
+			op, err := core.OutPointFromBytes(v)
+			if err != nil {
+				return err
+			}
+			output, err := getUTXO(tx, op)
+			if err != nil {
+				return err
+			}
+			outputs = append(outputs, output)
+			return nil
+		})
+	})
+
+	return outputs, err
+}
+
+// GetOutput returns the stored output referenced by the outpoint, or nil if
+// the outpoint does not belong to any registered address.
+func (t *DataStore) GetOutput(op *core.OutPoint) (output *UTXO, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	err = t.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(BKTOps).Get(op.Bytes()) == nil {
+			return nil
+		}
+		output, err = getUTXO(tx, op)
+		return err
+	})
+
+	return output, err
+}
+
+func (t *DataStore) GetSpend(op *core.OutPoint) (spend *Spend, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	err = t.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(BKTSpends).Get(op.Bytes())
+		if data == nil {
+			return nil
+		}
+		spend = new(Spend)
+		return spend.Deserialize(bytes.NewReader(data))
+	})
+
+	return spend, err
+}
+
+func (t *DataStore) GetAddrTxCount(hash *common.Uint168) (count int, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	err = t.View(func(tx *bolt.Tx) error {
+		prefix := hash.Bytes()
+		c := tx.Bucket(BKTAddrTxs).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// GetAddrTxs returns the transaction history of the given address ordered by
+// height, newest first if reverse is set. The query starts right after the
+// entry pointed by cursor, or from the beginning if cursor is nil, and skips
+// the first skip entries then returns at most count entries.
+func (t *DataStore) GetAddrTxs(hash *common.Uint168, cursor []byte,
+	skip, count int, reverse bool) (addrTxs []*AddrTx, err error) {
+	if cursor != nil && len(cursor) != AddrTxCursorSize {
+		return nil, fmt.Errorf("invalid cursor length %d", len(cursor))
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	err = t.View(func(tx *bolt.Tx) error {
+		prefix := hash.Bytes()
+		c := tx.Bucket(BKTAddrTxs).Cursor()
+
+		var k, v []byte
+		if reverse {
+			if cursor == nil {
+				cursor = bytes.Repeat([]byte{0xff}, AddrTxCursorSize)
+			}
+			k, v = c.Seek(addrTxKey(hash, cursor))
+			if k == nil {
+				k, v = c.Last()
+			} else {
+				k, v = c.Prev()
+			}
+		} else {
+			start := addrTxKey(hash, cursor)
+			k, v = c.Seek(start)
+			if cursor != nil && bytes.Equal(k, start) {
+				k, v = c.Next()
+			}
+		}
+
+		for k != nil && bytes.HasPrefix(k, prefix) && len(addrTxs) < count {
+			if skip > 0 {
+				skip--
+			} else {
+				addrTx, err := addrTxFromKeyValue(k[len(prefix):], v)
+				if err != nil {
+					return err
+				}
+				addrTxs = append(addrTxs, addrTx)
+			}
+
+			if reverse {
+				k, v = c.Prev()
+			} else {
+				k, v = c.Next()
+			}
+		}
+		return nil
+	})
+
+	return addrTxs, err
+}
+
 func (t *DataStore) Rollback(height uint32) error {
 	t.Lock()
 	defer t.Unlock()
@@ -226,6 +726,9 @@ func (t *DataStore) Rollback(height uint32) error {
 		var key [4]byte
 		binary.LittleEndian.PutUint32(key[:], height)
 		data := tx.Bucket(BKTHeightTxs).Get(key[:])
+		if data == nil {
+			return nil
+		}
 
 		var txMap = make(map[common.Uint256]uint32)
 		err := gob.NewDecoder(bytes.NewReader(data)).Decode(&txMap)
@@ -233,23 +736,59 @@ func (t *DataStore) Rollback(height uint32) error {
 			return err
 		}
 
+		txns := make([]*StoreTx, 0, len(txMap))
 		for hash := range txMap {
-			var txn core.Transaction
+			var txn StoreTx
 			data := tx.Bucket(BKTTxs).Get(hash.Bytes())
 			if err = txn.Deserialize(bytes.NewReader(data)); err != nil {
 				return err
 			}
-			for index, output := range txn.Outputs {
-				if t.filter.ContainAddr(output.ProgramHash) {
-					outpoint := core.NewOutPoint(txn.Hash(), uint16(index)).Bytes()
-					tx.Bucket(BKTOps).Delete(outpoint)
+			txns = append(txns, &txn)
+		}
+
+		// Remove address history before the outpoints, a transaction may
+		// spend an output created in the same block
+		for _, txn := range txns {
+			addrs, err := txnAddrs(tx, txn)
+			if err != nil {
+				return err
+			}
+			cursor := NewAddrTx(txn.Hash(), txn.Height).Cursor()
+			for addr := range addrs {
+				tx.Bucket(BKTAddrTxs).Delete(addrTxKey(&addr, cursor))
+			}
+		}
+
+		// Outpoints of unregistered addresses are kept without purge, so
+		// they are removed whether the address is in the filter or not
+		for _, txn := range txns {
+			hash := txn.Hash()
+			for index := range txn.Outputs {
+				outpoint := core.NewOutPoint(hash, uint16(index)).Bytes()
+				tx.Bucket(BKTOps).Delete(outpoint)
+			}
+			for _, input := range txn.Inputs {
+				outpoint := input.Previous.Bytes()
+				data := tx.Bucket(BKTSpends).Get(outpoint)
+				if data == nil {
+					continue
+				}
+				var spend Spend
+				if err = spend.Deserialize(bytes.NewReader(data)); err != nil {
+					return err
+				}
+				if spend.TxID == hash {
+					tx.Bucket(BKTSpends).Delete(outpoint)
 				}
 			}
 			if err = tx.Bucket(BKTTxs).Delete(hash.Bytes()); err != nil {
 				return err
 			}
+			if err = tx.Bucket(BKTProofs).Delete(hash.Bytes()); err != nil {
+				return err
+			}
 		}
-		return nil
+		return tx.Bucket(BKTHeightTxs).Delete(key[:])
 	})
 }
 
@@ -272,3 +811,201 @@ func (t *DataStore) Close() {
 	t.Lock()
 	t.DB.Close()
 }
+
+// backfillSpends records the spends of stored outpoints by the inputs of
+// stored transactions, which were not tracked before version 1.
+func backfillSpends(tx *bolt.Tx) error {
+	return tx.Bucket(BKTTxs).ForEach(func(k, v []byte) error {
+		var txn StoreTx
+		if err := txn.Deserialize(bytes.NewReader(v)); err != nil {
+			return err
+		}
+		for index, input := range txn.Inputs {
+			outpoint := tx.Bucket(BKTOps).Get(input.Previous.Bytes())
+			if outpoint == nil || tx.Bucket(BKTSpends).Get(outpoint) != nil {
+				continue
+			}
+			buf := new(bytes.Buffer)
+			spend := NewSpend(txn.Hash(), uint16(index), txn.Height)
+			if err := spend.Serialize(buf); err != nil {
+				return err
+			}
+			if err := tx.Bucket(BKTSpends).Put(outpoint, buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// backfillAddrTxs indexes the stored transactions by the registered addresses
+// they touch, which were not indexed before version 2.
+func (t *DataStore) backfillAddrTxs(tx *bolt.Tx) error {
+	return tx.Bucket(BKTTxs).ForEach(func(k, v []byte) error {
+		var txn StoreTx
+		if err := txn.Deserialize(bytes.NewReader(v)); err != nil {
+			return err
+		}
+		return t.putAddrTxs(tx, &txn)
+	})
+}
+
+// txnMatches checks if the transaction still pays to an address in filter or
+// spends a stored outpoint.
+func txnMatches(tx *bolt.Tx, filter *sdk.AddrFilter, txn *StoreTx) bool {
+	for _, output := range txn.Outputs {
+		if filter.ContainAddr(output.ProgramHash) {
+			return true
+		}
+	}
+	for _, input := range txn.Inputs {
+		if tx.Bucket(BKTOps).Get(input.Previous.Bytes()) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteHeightTx(tx *bolt.Tx, height uint32, txId common.Uint256) error {
+	var key [4]byte
+	binary.LittleEndian.PutUint32(key[:], height)
+	data := tx.Bucket(BKTHeightTxs).Get(key[:])
+	if data == nil {
+		return nil
+	}
+
+	var txMap = make(map[common.Uint256]uint32)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&txMap); err != nil {
+		return err
+	}
+
+	delete(txMap, txId)
+	if len(txMap) == 0 {
+		return tx.Bucket(BKTHeightTxs).Delete(key[:])
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(txMap); err != nil {
+		return err
+	}
+	return tx.Bucket(BKTHeightTxs).Put(key[:], buf.Bytes())
+}
+
+// getAddrTxs collects the amounts the transaction received to and sent from
+// each registered address it touches.
+func (t *DataStore) getAddrTxs(tx *bolt.Tx, txn *StoreTx) (map[common.Uint168]*AddrTx, error) {
+	addrTxs := make(map[common.Uint168]*AddrTx)
+	getAddrTx := func(hash common.Uint168) *AddrTx {
+		addrTx, ok := addrTxs[hash]
+		if !ok {
+			addrTx = NewAddrTx(txn.Hash(), txn.Height)
+			addrTxs[hash] = addrTx
+		}
+		return addrTx
+	}
+
+	for _, output := range txn.Outputs {
+		if t.filter.ContainAddr(output.ProgramHash) {
+			getAddrTx(output.ProgramHash).Received[output.AssetID] += output.Value
+		}
+	}
+
+	for _, input := range txn.Inputs {
+		if tx.Bucket(BKTOps).Get(input.Previous.Bytes()) == nil {
+			continue
+		}
+		utxo, err := getUTXO(tx, &input.Previous)
+		if err != nil {
+			return nil, err
+		}
+		getAddrTx(utxo.ProgramHash).Sent[utxo.AssetID] += utxo.Value
+	}
+
+	return addrTxs, nil
+}
+
+// txnAddrs collects all the addresses the transaction may be recorded in the
+// history of, including those no longer registered.
+func txnAddrs(tx *bolt.Tx, txn *StoreTx) (map[common.Uint168]struct{}, error) {
+	addrs := make(map[common.Uint168]struct{})
+	for _, output := range txn.Outputs {
+		addrs[output.ProgramHash] = struct{}{}
+	}
+	for _, input := range txn.Inputs {
+		if tx.Bucket(BKTOps).Get(input.Previous.Bytes()) == nil {
+			continue
+		}
+		utxo, err := getUTXO(tx, &input.Previous)
+		if err != nil {
+			return nil, err
+		}
+		addrs[utxo.ProgramHash] = struct{}{}
+	}
+	return addrs, nil
+}
+
+// putAddrTxs adds the transaction to the history of the registered addresses
+// it touches.
+func (t *DataStore) putAddrTxs(tx *bolt.Tx, txn *StoreTx) error {
+	addrTxs, err := t.getAddrTxs(tx, txn)
+	if err != nil {
+		return err
+	}
+	for addr, addrTx := range addrTxs {
+		buf := new(bytes.Buffer)
+		err = gob.NewEncoder(buf).Encode(addrTxValue{addrTx.Received, addrTx.Sent})
+		if err != nil {
+			return err
+		}
+		key := addrTxKey(&addr, addrTx.Cursor())
+		if err = tx.Bucket(BKTAddrTxs).Put(key, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type addrTxValue struct {
+	Received map[common.Uint256]common.Fixed64
+	Sent     map[common.Uint256]common.Fixed64
+}
+
+func addrTxFromKeyValue(cursor, value []byte) (*AddrTx, error) {
+	if len(cursor) != AddrTxCursorSize {
+		return nil, fmt.Errorf("invalid address transaction key length %d", len(cursor))
+	}
+	var txId common.Uint256
+	copy(txId[:], cursor[4:])
+	addrTx := NewAddrTx(txId, binary.BigEndian.Uint32(cursor[:4]))
+
+	var amounts addrTxValue
+	if err := gob.NewDecoder(bytes.NewReader(value)).Decode(&amounts); err != nil {
+		return nil, err
+	}
+	if amounts.Received != nil {
+		addrTx.Received = amounts.Received
+	}
+	if amounts.Sent != nil {
+		addrTx.Sent = amounts.Sent
+	}
+	return addrTx, nil
+}
+
+func getUTXO(tx *bolt.Tx, op *core.OutPoint) (*UTXO, error) {
+	data := tx.Bucket(BKTTxs).Get(op.TxID.Bytes())
+	if data == nil {
+		return nil, fmt.Errorf("transaction %s does not exist in database", op.TxID.String())
+	}
+
+	var txn StoreTx
+	if err := txn.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	if int(op.Index) >= len(txn.Outputs) {
+		return nil, fmt.Errorf("output index %d out of range in transaction %s",
+			op.Index, op.TxID.String())
+	}
+
+	return NewUTXO(op, txn.Height, txn.Outputs[op.Index]), nil
+}
@@ -0,0 +1,345 @@
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+// useTempDir changes the working directory to a temporary directory, where
+// the stores create their database files. The returned function restores
+// the working directory and removes the temporary one.
+func useTempDir(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "spvnode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+}
+
+// openTestDataStore opens a data store in a temporary directory, the
+// returned function removes the directory after the store closed.
+func openTestDataStore(t *testing.T) (*DataStore, func()) {
+	cleanup := useTempDir(t)
+	store, err := NewDataStore()
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return store, cleanup
+}
+
+func reopenTestDataStore(t *testing.T, store *DataStore) *DataStore {
+	store.Close()
+	store, err := NewDataStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func testAddr(t *testing.T, store *DataStore, seed byte) common.Uint168 {
+	var hash common.Uint168
+	hash[0] = 0x21
+	hash[1] = seed
+	address, err := hash.ToAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store != nil {
+		addr, err := NewStoreAddr(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.PutAddr(addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return hash
+}
+
+func testTx(height uint32, inputs []*core.OutPoint, outputs ...*core.Output) *StoreTx {
+	tx := &core.Transaction{TxType: core.TransferAsset, LockTime: height}
+	for _, op := range inputs {
+		tx.Inputs = append(tx.Inputs, &core.Input{Previous: *op})
+	}
+	tx.Outputs = outputs
+	return NewStoreTx(tx, height)
+}
+
+func testOutput(hash common.Uint168, value common.Fixed64) *core.Output {
+	return &core.Output{AssetID: AssetEla, Value: value, ProgramHash: hash}
+}
+
+func putTestTx(t *testing.T, store *DataStore, txn *StoreTx) {
+	fPositive, err := store.PutTx(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fPositive {
+		t.Fatalf("transaction %s is false positive", txn.Hash().String())
+	}
+}
+
+func utxoValues(t *testing.T, store *DataStore) map[core.OutPoint]common.Fixed64 {
+	utxos, err := store.GetUTXOs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[core.OutPoint]common.Fixed64)
+	for _, utxo := range utxos {
+		values[utxo.Op] = utxo.Value
+	}
+	return values
+}
+
+func TestDataStoreSpends(t *testing.T) {
+	store, cleanup := openTestDataStore(t)
+	defer cleanup()
+	defer func() { store.Close() }()
+
+	addr := testAddr(t, store, 1)
+	other := testAddr(t, nil, 2)
+
+	receive := testTx(1, nil, testOutput(addr, 100), testOutput(addr, 50))
+	putTestTx(t, store, receive)
+	spend := testTx(2, []*core.OutPoint{core.NewOutPoint(receive.Hash(), 0)},
+		testOutput(other, 90), testOutput(addr, 10))
+	putTestTx(t, store, spend)
+
+	expect := map[core.OutPoint]common.Fixed64{
+		*core.NewOutPoint(receive.Hash(), 1): 50,
+		*core.NewOutPoint(spend.Hash(), 1):   10,
+	}
+	checkUTXOs := func() {
+		values := utxoValues(t, store)
+		if len(values) != len(expect) {
+			t.Fatalf("got %d utxos, expect %d", len(values), len(expect))
+		}
+		for op, value := range expect {
+			if values[op] != value {
+				t.Fatalf("utxo %s:%d value %s, expect %s",
+					op.TxID.String(), op.Index, values[op].String(), value.String())
+			}
+		}
+	}
+	checkUTXOs()
+
+	// A database without the spends index is migrated on open
+	err := store.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(BKTSpends); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(BKTSpends); err != nil {
+			return err
+		}
+		return tx.Bucket(BKTMeta).Delete(KEYVersion)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store = reopenTestDataStore(t, store)
+	checkUTXOs()
+
+	spent, err := store.GetSpend(core.NewOutPoint(receive.Hash(), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spent == nil || spent.TxID != spend.Hash() || spent.Height != 2 {
+		t.Fatalf("unexpected spend %v", spent)
+	}
+}
+
+func addrHistory(t *testing.T, store *DataStore, hash common.Uint168) []*AddrTx {
+	addrTxs, err := store.GetAddrTxs(&hash, nil, 0, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addrTxs
+}
+
+func TestDataStoreAddrTxs(t *testing.T) {
+	store, cleanup := openTestDataStore(t)
+	defer cleanup()
+	defer func() { store.Close() }()
+
+	addr := testAddr(t, store, 1)
+	other := testAddr(t, nil, 2)
+
+	receive := testTx(1, nil, testOutput(addr, 100))
+	putTestTx(t, store, receive)
+	spend := testTx(2, []*core.OutPoint{core.NewOutPoint(receive.Hash(), 0)},
+		testOutput(other, 70), testOutput(addr, 30))
+	putTestTx(t, store, spend)
+
+	checkHistory := func() {
+		history := addrHistory(t, store, addr)
+		if len(history) != 2 {
+			t.Fatalf("got %d history entries, expect 2", len(history))
+		}
+		if history[0].TxID != receive.Hash() || history[0].Delta(AssetEla) != 100 {
+			t.Fatalf("unexpected receive entry %v", history[0])
+		}
+		if history[1].TxID != spend.Hash() || history[1].Delta(AssetEla) != -70 {
+			t.Fatalf("unexpected spend entry %v", history[1])
+		}
+	}
+	checkHistory()
+
+	// Continue right after the first entry
+	next, err := store.GetAddrTxs(&addr, addrHistory(t, store, addr)[0].Cursor(), 0, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next) != 1 || next[0].TxID != spend.Hash() {
+		t.Fatalf("unexpected history after cursor %v", next)
+	}
+	if _, err := store.GetAddrTxs(&addr, []byte{1, 2, 3}, 0, 100, false); err == nil {
+		t.Fatal("invalid cursor accepted")
+	}
+
+	// A database without the history index is migrated on open
+	err = store.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(BKTAddrTxs); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(BKTAddrTxs); err != nil {
+			return err
+		}
+		return tx.Bucket(BKTMeta).Delete(KEYVersion)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store = reopenTestDataStore(t, store)
+	checkHistory()
+}
+
+func TestDataStoreRollback(t *testing.T) {
+	store, cleanup := openTestDataStore(t)
+	defer cleanup()
+	defer func() { store.Close() }()
+
+	addr := testAddr(t, store, 1)
+	other := testAddr(t, nil, 2)
+
+	base := testTx(1, nil, testOutput(addr, 100))
+	putTestTx(t, store, base)
+
+	// The second transaction spends an output created in the same block
+	receive := testTx(2, nil, testOutput(addr, 50))
+	putTestTx(t, store, receive)
+	spend := testTx(2, []*core.OutPoint{core.NewOutPoint(receive.Hash(), 0)},
+		testOutput(other, 50))
+	putTestTx(t, store, spend)
+
+	if err := store.Rollback(2); err != nil {
+		t.Fatal(err)
+	}
+
+	history := addrHistory(t, store, addr)
+	if len(history) != 1 || history[0].TxID != base.Hash() {
+		t.Fatalf("unexpected history after rollback %v", history)
+	}
+	values := utxoValues(t, store)
+	if len(values) != 1 || values[*core.NewOutPoint(base.Hash(), 0)] != 100 {
+		t.Fatalf("unexpected utxos after rollback %v", values)
+	}
+	if store.HasTx(receive.Hash()) || store.HasTx(spend.Hash()) {
+		t.Fatal("rolled back transactions still stored")
+	}
+}
+
+func TestDataStoreDeleteAddrs(t *testing.T) {
+	store, cleanup := openTestDataStore(t)
+	defer cleanup()
+	defer func() { store.Close() }()
+
+	addr1 := testAddr(t, store, 1)
+	addr2 := testAddr(t, store, 2)
+	addr3 := testAddr(t, store, 3)
+	address1, _ := addr1.ToAddress()
+	address2, _ := addr2.ToAddress()
+	address3, _ := addr3.ToAddress()
+	unknown, _ := testAddr(t, nil, 4).ToAddress()
+
+	// A transaction pays to the first two addresses
+	shared := testTx(1, nil, testOutput(addr1, 10), testOutput(addr2, 20))
+	putTestTx(t, store, shared)
+	kept := testTx(2, nil, testOutput(addr3, 30))
+	putTestTx(t, store, kept)
+
+	// Nothing is removed if any address has not registered
+	if err := store.DeleteAddrs([]string{address1, unknown}, true); err == nil {
+		t.Fatal("unregistered address accepted")
+	}
+	if !store.ContainAddr(&addr1) || len(utxoValues(t, store)) != 3 {
+		t.Fatal("addresses removed partially")
+	}
+
+	if err := store.DeleteAddrs([]string{address1, address2}, true); err != nil {
+		t.Fatal(err)
+	}
+	if store.ContainAddr(&addr1) || store.ContainAddr(&addr2) || !store.ContainAddr(&addr3) {
+		t.Fatal("unexpected registered addresses")
+	}
+	if store.HasTx(shared.Hash()) || !store.HasTx(kept.Hash()) {
+		t.Fatal("unexpected stored transactions")
+	}
+	if values := utxoValues(t, store); len(values) != 1 {
+		t.Fatalf("got %d utxos, expect 1", len(values))
+	}
+
+	// Outpoints are kept without purge
+	if ok, err := store.DeleteAddr(address3, false); !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	if values := utxoValues(t, store); len(values) != 1 {
+		t.Fatalf("got %d utxos, expect 1", len(values))
+	}
+}
+
+func TestDataStoreRollbackDeletedAddr(t *testing.T) {
+	store, cleanup := openTestDataStore(t)
+	defer cleanup()
+	defer func() { store.Close() }()
+
+	addr := testAddr(t, store, 1)
+	other := testAddr(t, store, 2)
+	address, _ := addr.ToAddress()
+
+	base := testTx(1, nil, testOutput(other, 100))
+	putTestTx(t, store, base)
+	receive := testTx(2, nil, testOutput(addr, 50))
+	putTestTx(t, store, receive)
+
+	// The outpoints of the address are kept without purge
+	if ok, err := store.DeleteAddr(address, false); !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	if err := store.Rollback(2); err != nil {
+		t.Fatal(err)
+	}
+
+	values := utxoValues(t, store)
+	if len(values) != 1 || values[*core.NewOutPoint(base.Hash(), 0)] != 100 {
+		t.Fatalf("unexpected utxos after rollback %v", values)
+	}
+	if history := addrHistory(t, store, addr); len(history) != 0 {
+		t.Fatalf("unexpected history after rollback %v", history)
+	}
+}
@@ -1,15 +1,18 @@
 package node
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/elastos/Elastos.ELA.SPV/store"
 	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
 
 	"github.com/boltdb/bolt"
 	"github.com/cevaris/ordered_map"
@@ -19,9 +22,16 @@ var (
 	BKTHeaders    = []byte("Headers")
 	BKTHeightHash = []byte("HeightHash")
 	BKTChainTip   = []byte("ChainTip")
-	KEYChainTip   = []byte("ChainTip")
+	// BKTForkTips indexes the tips of side branches by hash, with the
+	// height as value.
+	BKTForkTips = []byte("ForkTips")
+	KEYChainTip = []byte("ChainTip")
 )
 
+// medianTimeBlocks is the number of blocks to calculate the median time
+// past of a block, including the block itself.
+const medianTimeBlocks = 11
+
 type HeaderStore struct {
 	*sync.RWMutex
 	*bolt.DB
@@ -47,6 +57,10 @@ func NewHeaderStore() (*HeaderStore, error) {
 		if err != nil {
 			return err
 		}
+		// Index the fork tips of a database created before the index
+		if btx.Bucket(BKTForkTips) == nil {
+			return indexForkTips(btx)
+		}
 		return nil
 	})
 
@@ -81,6 +95,13 @@ func (h *HeaderStore) initCache() {
 }
 
 func (h *HeaderStore) PutHeader(header *store.StoreHeader, newTip bool) error {
+	// Reject the headers failing proof of work, so a chain can not be
+	// forged without the work
+	if err := CheckProofOfWork(&header.Header); err != nil {
+		return fmt.Errorf("header %s at height %d: %s",
+			header.Hash().String(), header.Height, err)
+	}
+
 	h.Lock()
 	defer h.Unlock()
 
@@ -95,22 +116,68 @@ func (h *HeaderStore) PutHeader(header *store.StoreHeader, newTip bool) error {
 			return err
 		}
 
-		err = tx.Bucket(BKTHeaders).Put(header.Hash().Bytes(), bytes)
+		hash := header.Hash()
+		err = tx.Bucket(BKTHeaders).Put(hash.Bytes(), bytes)
 		if err != nil {
 			return err
 		}
 
-		if newTip {
-			err = tx.Bucket(BKTChainTip).Put(KEYChainTip, bytes)
-			if err != nil {
-				return err
+		// The previous header is not a tip anymore
+		forks := tx.Bucket(BKTForkTips)
+		if err := forks.Delete(header.Previous.Bytes()); err != nil {
+			return err
+		}
+		if !newTip {
+			var height [4]byte
+			binary.LittleEndian.PutUint32(height[:], header.Height)
+			return forks.Put(hash.Bytes(), height[:])
+		}
+
+		// The best chain switched, the previous tip becomes a fork tip
+		if tip, err := getHeader(tx, BKTChainTip, KEYChainTip); err == nil {
+			if tipHash := tip.Hash(); tipHash != header.Previous {
+				var height [4]byte
+				binary.LittleEndian.PutUint32(height[:], tip.Height)
+				if err := forks.Put(tipHash.Bytes(), height[:]); err != nil {
+					return err
+				}
 			}
 		}
 
+		err = tx.Bucket(BKTChainTip).Put(KEYChainTip, bytes)
+		if err != nil {
+			return err
+		}
+
+		return putHeightHashes(tx, header)
+	})
+}
+
+// putHeightHashes maps the heights to the headers from the new tip back to
+// the fork point, where the height maps to the header already.
+func putHeightHashes(tx *bolt.Tx, header *store.StoreHeader) error {
+	heightHash := tx.Bucket(BKTHeightHash)
+	for {
 		var key [4]byte
 		binary.LittleEndian.PutUint32(key[:], header.Height)
-		return tx.Bucket(BKTHeightHash).Put(key[:], header.Hash().Bytes())
-	})
+		hash := header.Hash()
+		if bytes.Equal(heightHash.Get(key[:]), hash.Bytes()) {
+			return nil
+		}
+		if err := heightHash.Put(key[:], hash.Bytes()); err != nil {
+			return err
+		}
+
+		// Genesis block is not stored
+		if header.Height <= 1 {
+			return nil
+		}
+		previous, err := getHeader(tx, BKTHeaders, header.Previous.Bytes())
+		if err != nil {
+			return err
+		}
+		header = previous
+	}
 }
 
 func (h *HeaderStore) GetPrevious(header *store.StoreHeader) (*store.StoreHeader, error) {
@@ -182,6 +249,206 @@ func (h *HeaderStore) GetHeaderHash(height uint32) (hash *common.Uint256, err er
 	return hash, err
 }
 
+// Rewind resets the chain tip to the header on the given height, so the
+// synchronization will start again from the next height. Headers above it
+// are kept in database and will be overwritten on synchronize.
+func (h *HeaderStore) Rewind(height uint32) error {
+	h.Lock()
+	defer h.Unlock()
+
+	var tip *store.StoreHeader
+	err := h.Update(func(tx *bolt.Tx) error {
+		// Genesis block is not stored, rewind to it by removing the chain tip
+		if height == 0 {
+			return tx.Bucket(BKTChainTip).Delete(KEYChainTip)
+		}
+
+		var key [4]byte
+		binary.LittleEndian.PutUint32(key[:], height)
+		hash := tx.Bucket(BKTHeightHash).Get(key[:])
+		if hash == nil {
+			return fmt.Errorf("header hash not exist on height %d", height)
+		}
+
+		header, err := getHeader(tx, BKTHeaders, hash)
+		if err != nil {
+			return err
+		}
+
+		bytes, err := header.Serialize()
+		if err != nil {
+			return err
+		}
+
+		tip = header
+		return tx.Bucket(BKTChainTip).Put(KEYChainTip, bytes)
+	})
+	if err != nil {
+		return err
+	}
+
+	h.cache.tip = tip
+	return nil
+}
+
+// GetHeightByTime returns the height of the first block with timestamp not
+// earlier than the given time, or the next height if no such block yet.
+func (h *HeaderStore) GetHeightByTime(timestamp uint32) (uint32, error) {
+	best, err := h.GetBestHeader()
+	if err != nil {
+		return 0, err
+	}
+	if best.Timestamp < timestamp {
+		return best.Height + 1, nil
+	}
+
+	low, high := uint32(1), best.Height
+	for low < high {
+		mid := low + (high-low)/2
+		hash, err := h.GetHeaderHash(mid)
+		if err != nil {
+			return 0, err
+		}
+		header, err := h.GetHeader(hash)
+		if err != nil {
+			return 0, err
+		}
+		if header.Timestamp < timestamp {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+// ChainTip is the tip of a branch in the header store.
+type ChainTip struct {
+	Height uint32
+	Hash   common.Uint256
+	// BranchLen is the number of headers from the tip to the fork point
+	// on the best chain, 0 for the best chain tip.
+	BranchLen uint32
+}
+
+// GetChainTips returns the best chain tip and the tips of side branches.
+// Headers rewound by rescan are not side branches, they are on the best
+// chain and will be overwritten on synchronize.
+func (h *HeaderStore) GetChainTips() ([]*ChainTip, error) {
+	best, err := h.GetBestHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	h.RLock()
+	defer h.RUnlock()
+
+	tips := []*ChainTip{{Height: best.Height, Hash: best.Hash()}}
+	err = h.View(func(tx *bolt.Tx) error {
+		heightHash := tx.Bucket(BKTHeightHash)
+		return tx.Bucket(BKTForkTips).ForEach(func(k, v []byte) error {
+			header, err := getHeader(tx, BKTHeaders, k)
+			if err != nil {
+				return err
+			}
+
+			tip := &ChainTip{Height: header.Height, Hash: header.Hash()}
+			for {
+				var key [4]byte
+				binary.LittleEndian.PutUint32(key[:], header.Height)
+				hash := header.Hash()
+				if bytes.Equal(heightHash.Get(key[:]), hash.Bytes()) || header.Height <= 1 {
+					break
+				}
+				tip.BranchLen++
+				if header, err = getHeader(tx, BKTHeaders, header.Previous.Bytes()); err != nil {
+					return err
+				}
+			}
+			// The tip may be switched back to the best chain
+			if tip.BranchLen > 0 {
+				tips = append(tips, tip)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tips, nil
+}
+
+// indexForkTips creates the fork tips index from the stored headers, the
+// headers above the best height are rewound and not indexed.
+func indexForkTips(tx *bolt.Tx) error {
+	forks, err := tx.CreateBucket(BKTForkTips)
+	if err != nil {
+		return err
+	}
+	best, err := getHeader(tx, BKTChainTip, KEYChainTip)
+	if err != nil {
+		// No headers stored yet
+		return nil
+	}
+
+	heightHash := tx.Bucket(BKTHeightHash)
+	sides := make(map[common.Uint256]uint32)
+	parents := make(map[common.Uint256]struct{})
+	err = tx.Bucket(BKTHeaders).ForEach(func(k, v []byte) error {
+		var header store.StoreHeader
+		if err := header.Deserialize(v); err != nil {
+			return err
+		}
+		if header.Height > best.Height {
+			return nil
+		}
+		var key [4]byte
+		binary.LittleEndian.PutUint32(key[:], header.Height)
+		if bytes.Equal(heightHash.Get(key[:]), k) {
+			return nil
+		}
+		sides[header.Hash()] = header.Height
+		parents[header.Previous] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for hash, height := range sides {
+		if _, ok := parents[hash]; ok {
+			continue
+		}
+		var value [4]byte
+		binary.LittleEndian.PutUint32(value[:], height)
+		if err := forks.Put(hash.Bytes(), value[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMedianTimePast returns the median timestamp of the header and it's
+// previous headers, medianTimeBlocks headers at most.
+func (h *HeaderStore) GetMedianTimePast(header *core.Header) (uint32, error) {
+	timestamps := make([]uint32, 0, medianTimeBlocks)
+	for {
+		timestamps = append(timestamps, header.Timestamp)
+		// Genesis block is not stored
+		if len(timestamps) == medianTimeBlocks || header.Height <= 1 {
+			break
+		}
+		previous, err := h.GetHeader(&header.Previous)
+		if err != nil {
+			return 0, err
+		}
+		header = &previous.Header
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	return timestamps[len(timestamps)/2], nil
+}
+
 func (h *HeaderStore) Reset() error {
 	h.Lock()
 	defer h.Unlock()
@@ -192,6 +459,11 @@ func (h *HeaderStore) Reset() error {
 			return err
 		}
 
+		err = tx.DeleteBucket(BKTForkTips)
+		if err != nil {
+			return err
+		}
+
 		return tx.DeleteBucket(BKTChainTip)
 	})
 }
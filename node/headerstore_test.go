@@ -0,0 +1,134 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/elastos/Elastos.ELA.SPV/store"
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+// openTestHeaderStore opens a header store in a temporary directory, the
+// returned function removes the directory after the store closed.
+func openTestHeaderStore(t *testing.T) (*HeaderStore, func()) {
+	cleanup := useTempDir(t)
+	headers, err := NewHeaderStore()
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return headers, cleanup
+}
+
+func putTestHeader(t *testing.T, headers *HeaderStore, previous *store.StoreHeader,
+	nonce uint32, newTip bool) *store.StoreHeader {
+	header := &store.StoreHeader{
+		Header:    core.Header{Height: 1, Nonce: nonce},
+		TotalWork: new(big.Int),
+	}
+	if previous != nil {
+		header.Height = previous.Height + 1
+		header.Previous = previous.Hash()
+	}
+	mineTestHeader(t, &header.Header)
+	if err := headers.PutHeader(header, newTip); err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func checkChainTips(t *testing.T, headers *HeaderStore, expect map[common.Uint256]uint32) {
+	tips, err := headers.GetChainTips()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tips) != len(expect) {
+		t.Fatalf("got %d chain tips, expect %d", len(tips), len(expect))
+	}
+	for _, tip := range tips {
+		branchLen, ok := expect[tip.Hash]
+		if !ok || tip.BranchLen != branchLen {
+			t.Fatalf("unexpected chain tip at height %d with branch length %d",
+				tip.Height, tip.BranchLen)
+		}
+	}
+}
+
+func TestHeaderStoreChainTips(t *testing.T) {
+	defer useTestPowLimit()()
+	headers, cleanup := openTestHeaderStore(t)
+	defer cleanup()
+	defer func() { headers.Close() }()
+
+	var chain []*store.StoreHeader
+	var previous *store.StoreHeader
+	for i := 0; i < 5; i++ {
+		previous = putTestHeader(t, headers, previous, 0, true)
+		chain = append(chain, previous)
+	}
+
+	// A side branch forks from the third header
+	side := putTestHeader(t, headers, chain[2], 1, false)
+	checkChainTips(t, headers, map[common.Uint256]uint32{
+		chain[4].Hash(): 0,
+		side.Hash():     1,
+	})
+
+	// The side branch becomes the best chain
+	side = putTestHeader(t, headers, side, 1, false)
+	side = putTestHeader(t, headers, side, 1, true)
+	expect := map[common.Uint256]uint32{
+		side.Hash():     0,
+		chain[4].Hash(): 2,
+	}
+	checkChainTips(t, headers, expect)
+	hash, err := headers.GetHeaderHash(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *hash == chain[3].Hash() {
+		t.Fatal("height not switched to the best chain")
+	}
+
+	// The index is created for a database without it
+	err = headers.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(BKTForkTips)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers.Close()
+	if headers, err = NewHeaderStore(); err != nil {
+		t.Fatal(err)
+	}
+	checkChainTips(t, headers, expect)
+
+	// Headers rewound by rescan are not side branches
+	if err := headers.Rewind(3); err != nil {
+		t.Fatal(err)
+	}
+	checkChainTips(t, headers, map[common.Uint256]uint32{
+		chain[2].Hash(): 0,
+		chain[4].Hash(): 2,
+	})
+}
+
+func TestHeaderStoreProofOfWork(t *testing.T) {
+	defer useTestPowLimit()()
+	headers, cleanup := openTestHeaderStore(t)
+	defer cleanup()
+	defer func() { headers.Close() }()
+
+	header := &store.StoreHeader{
+		Header:    core.Header{Height: 1, Bits: 0x217fffff},
+		TotalWork: new(big.Int),
+	}
+	if err := headers.PutHeader(header, true); err == nil {
+		t.Fatal("header failing proof of work accepted")
+	}
+	if _, err := headers.GetBestHeader(); err == nil {
+		t.Fatal("header failing proof of work stored")
+	}
+}
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"sync"
+
+	"github.com/elastos/Elastos.ELA.SPV/sdk"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+// Listener receives the events of SPV node.
+type Listener interface {
+	// OnBlock is called when a block has been committed, with the matched
+	// transactions newly stored in this block. rescanned is set if the block
+	// was committed before and synchronized again by rescan, the block and
+	// confirmation events of it have been notified already.
+	OnBlock(header *core.Header, txs []*core.Transaction, rescanned bool)
+
+	// OnRollback is called when the block on height has been removed from
+	// the chain, with the transactions removed.
+	OnRollback(height uint32, txs []*core.Transaction)
+
+	// OnStateChange is called when the synchronize state changed.
+	OnStateChange(state sdk.ChainState)
+}
+
+type listeners struct {
+	sync.RWMutex
+	list []Listener
+
+	// newly stored transactions waiting for their block committed
+	pending []*core.Transaction
+}
+
+func (l *listeners) add(listener Listener) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.list = append(l.list, listener)
+}
+
+func (l *listeners) addPending(tx *core.Transaction) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.pending = append(l.pending, tx)
+}
+
+// dropPending removes the transactions waiting for their block committed,
+// they will not be notified.
+func (l *listeners) dropPending() {
+	l.Lock()
+	defer l.Unlock()
+
+	l.pending = nil
+}
+
+func (l *listeners) notifyBlock(header *core.Header, rescanned bool) {
+	l.Lock()
+	txs := l.pending
+	l.pending = nil
+	list := l.list
+	l.Unlock()
+
+	for _, listener := range list {
+		listener.OnBlock(header, txs, rescanned)
+	}
+}
+
+func (l *listeners) notifyRollback(height uint32, txs []*core.Transaction) {
+	l.RLock()
+	defer l.RUnlock()
+
+	for _, listener := range l.list {
+		listener.OnRollback(height, txs)
+	}
+}
+
+func (l *listeners) notifyStateChange(state sdk.ChainState) {
+	l.RLock()
+	defer l.RUnlock()
+
+	for _, listener := range l.list {
+		listener.OnStateChange(state)
+	}
+}
+
+func (n *SPVNode) AddListener(listener Listener) {
+	n.listeners.add(listener)
+}
@@ -0,0 +1,222 @@
+package node
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA.Utility/p2p/msg"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+// MerkleProof is the merkle branch proves a transaction is included in
+// the block with the block hash.
+type MerkleProof struct {
+	TxID         common.Uint256
+	BlockHash    common.Uint256
+	Height       uint32
+	Transactions uint32
+	Index        uint32
+	Branch       []common.Uint256
+}
+
+func (p *MerkleProof) Serialize(buf io.Writer) error {
+	if err := p.TxID.Serialize(buf); err != nil {
+		return err
+	}
+	if err := p.BlockHash.Serialize(buf); err != nil {
+		return err
+	}
+	err := binary.Write(buf, binary.LittleEndian,
+		[]uint32{p.Height, p.Transactions, p.Index, uint32(len(p.Branch))})
+	if err != nil {
+		return err
+	}
+	for _, hash := range p.Branch {
+		if err := hash.Serialize(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *MerkleProof) Deserialize(reader io.Reader) error {
+	if err := p.TxID.Deserialize(reader); err != nil {
+		return err
+	}
+	if err := p.BlockHash.Deserialize(reader); err != nil {
+		return err
+	}
+	var fields [4]uint32
+	if err := binary.Read(reader, binary.LittleEndian, fields[:]); err != nil {
+		return err
+	}
+	p.Height, p.Transactions, p.Index = fields[0], fields[1], fields[2]
+	if fields[3] > 32 {
+		return errors.New("merkle branch too long")
+	}
+	p.Branch = make([]common.Uint256, fields[3])
+	for i := range p.Branch {
+		if err := p.Branch[i].Deserialize(reader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Root computes the merkle root from the transaction ID and the branch. The
+// index and the branch length are checked against the number of transactions
+// in the block, so an inner node or the root itself can not be proved as a
+// transaction by a shortened branch.
+func (p *MerkleProof) Root() (common.Uint256, error) {
+	if p.Index >= p.Transactions {
+		return common.Uint256{}, errors.New("transaction index out of range")
+	}
+	tree := &partialMerkleTree{total: p.Transactions}
+	depth := uint32(0)
+	for tree.width(depth) > 1 {
+		depth++
+	}
+	if uint32(len(p.Branch)) != depth {
+		return common.Uint256{}, fmt.Errorf("merkle branch length %d, expect %d",
+			len(p.Branch), depth)
+	}
+
+	hash := p.TxID
+	index := p.Index
+	for height, sibling := range p.Branch {
+		if index&1 == 0 {
+			// The right node duplicates the left one if not exist
+			if index+1 >= tree.width(uint32(height)) && sibling != hash {
+				return common.Uint256{}, errors.New("merkle branch not duplicated")
+			}
+			hash = hashMerkleBranches(&hash, &sibling)
+		} else {
+			hash = hashMerkleBranches(&sibling, &hash)
+		}
+		index >>= 1
+	}
+	return hash, nil
+}
+
+// NewMerkleProofs extracts the merkle proofs of the matched transactions
+// from the partial merkle tree in the merkle block.
+func NewMerkleProofs(block *msg.MerkleBlock) ([]*MerkleProof, error) {
+	header, ok := block.Header.(*core.Header)
+	if !ok {
+		return nil, errors.New("unknown merkle block header type")
+	}
+	if block.Transactions == 0 {
+		return nil, errors.New("merkle block has no transactions")
+	}
+
+	tree := &partialMerkleTree{
+		total:  block.Transactions,
+		hashes: block.Hashes,
+		flags:  block.Flags,
+	}
+	height := uint32(0)
+	for tree.width(height) > 1 {
+		height++
+	}
+
+	root, proofs, err := tree.traverse(height, 0)
+	if err != nil {
+		return nil, err
+	}
+	if root != header.MerkleRoot {
+		return nil, errors.New("merkle root not match")
+	}
+
+	blockHash := header.Hash()
+	for _, proof := range proofs {
+		proof.BlockHash = blockHash
+		proof.Height = header.Height
+		proof.Transactions = block.Transactions
+	}
+	return proofs, nil
+}
+
+type partialMerkleTree struct {
+	total     uint32
+	hashes    []*common.Uint256
+	flags     []byte
+	hashIndex int
+	bitIndex  int
+}
+
+func (t *partialMerkleTree) width(height uint32) uint32 {
+	return (t.total + (1 << height) - 1) >> height
+}
+
+func (t *partialMerkleTree) nextBit() (bool, error) {
+	if t.bitIndex >= len(t.flags)*8 {
+		return false, errors.New("merkle block flags overflow")
+	}
+	bit := t.flags[t.bitIndex/8]&(1<<uint(t.bitIndex%8)) != 0
+	t.bitIndex++
+	return bit, nil
+}
+
+func (t *partialMerkleTree) nextHash() (common.Uint256, error) {
+	if t.hashIndex >= len(t.hashes) {
+		return common.Uint256{}, errors.New("merkle block hashes overflow")
+	}
+	hash := *t.hashes[t.hashIndex]
+	t.hashIndex++
+	return hash, nil
+}
+
+// traverse the tree in depth-first order, returns the hash of the node and
+// the proofs of matched transactions under it with branches up to the node.
+func (t *partialMerkleTree) traverse(height, pos uint32) (common.Uint256, []*MerkleProof, error) {
+	parentOfMatch, err := t.nextBit()
+	if err != nil {
+		return common.Uint256{}, nil, err
+	}
+
+	if height == 0 || !parentOfMatch {
+		hash, err := t.nextHash()
+		if err != nil {
+			return common.Uint256{}, nil, err
+		}
+		if height == 0 && parentOfMatch {
+			return hash, []*MerkleProof{{TxID: hash, Index: pos}}, nil
+		}
+		return hash, nil, nil
+	}
+
+	left, leftProofs, err := t.traverse(height-1, pos*2)
+	if err != nil {
+		return common.Uint256{}, nil, err
+	}
+	// The right node duplicates the left one if not exist
+	right, rightProofs := left, []*MerkleProof(nil)
+	if pos*2+1 < t.width(height-1) {
+		right, rightProofs, err = t.traverse(height-1, pos*2+1)
+		if err != nil {
+			return common.Uint256{}, nil, err
+		}
+	}
+
+	for _, proof := range leftProofs {
+		proof.Branch = append(proof.Branch, right)
+	}
+	for _, proof := range rightProofs {
+		proof.Branch = append(proof.Branch, left)
+	}
+	proofs := append(leftProofs, rightProofs...)
+
+	return hashMerkleBranches(&left, &right), proofs, nil
+}
+
+func hashMerkleBranches(left, right *common.Uint256) common.Uint256 {
+	var data [64]byte
+	copy(data[:32], left[:])
+	copy(data[32:], right[:])
+	first := sha256.Sum256(data[:])
+	return common.Uint256(sha256.Sum256(first[:]))
+}
@@ -0,0 +1,97 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+// testMerkleTree returns the levels of the merkle tree built from the
+// transaction hashes, from the leaves to the root.
+func testMerkleTree(txIds []common.Uint256) [][]common.Uint256 {
+	levels := [][]common.Uint256{txIds}
+	for level := txIds; len(level) > 1; {
+		var next []common.Uint256
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, hashMerkleBranches(&level[i], &right))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+func testMerkleBranch(levels [][]common.Uint256, index uint32) []common.Uint256 {
+	var branch []common.Uint256
+	for _, level := range levels[:len(levels)-1] {
+		sibling := index ^ 1
+		if sibling >= uint32(len(level)) {
+			sibling = index
+		}
+		branch = append(branch, level[sibling])
+		index >>= 1
+	}
+	return branch
+}
+
+func TestMerkleProofRoot(t *testing.T) {
+	txIds := make([]common.Uint256, 5)
+	for i := range txIds {
+		txIds[i][0] = byte(i + 1)
+	}
+	levels := testMerkleTree(txIds)
+	root := levels[len(levels)-1][0]
+
+	for i := range txIds {
+		proof := &MerkleProof{
+			TxID:         txIds[i],
+			Transactions: uint32(len(txIds)),
+			Index:        uint32(i),
+			Branch:       testMerkleBranch(levels, uint32(i)),
+		}
+		hash, err := proof.Root()
+		if err != nil {
+			t.Fatalf("proof of transaction %d rejected, %s", i, err)
+		}
+		if hash != root {
+			t.Fatalf("proof of transaction %d has wrong root", i)
+		}
+	}
+
+	forged := []struct {
+		name  string
+		proof *MerkleProof
+	}{
+		{"root with empty branch", &MerkleProof{
+			TxID:         root,
+			Transactions: uint32(len(txIds)),
+		}},
+		{"inner node with short branch", &MerkleProof{
+			TxID:         levels[1][0],
+			Transactions: uint32(len(txIds)),
+			Branch:       testMerkleBranch(levels[1:], 0),
+		}},
+		{"index out of range", &MerkleProof{
+			TxID:         txIds[4],
+			Transactions: uint32(len(txIds)),
+			Index:        5,
+			Branch:       testMerkleBranch(levels, 4),
+		}},
+		{"missing node not duplicated", &MerkleProof{
+			TxID:         txIds[4],
+			Transactions: uint32(len(txIds)),
+			Index:        4,
+			Branch: append([]common.Uint256{txIds[0]},
+				testMerkleBranch(levels, 4)[1:]...),
+		}},
+	}
+	for _, c := range forged {
+		if _, err := c.proof.Root(); err == nil {
+			t.Fatalf("forged proof of %s accepted", c.name)
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package node
+
+import (
+	"fmt"
+	stdnet "net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+	"github.com/elastos/Elastos.ELA.SPV/net"
+)
+
+const (
+	// DefaultBanDuration is the default time of setban.
+	DefaultBanDuration = 24 * time.Hour
+
+	// peerMaintainInterval is the interval to disconnect banned peers and
+	// reconnect added nodes.
+	peerMaintainInterval = 30 * time.Second
+
+	// peerPingInterval is the interval to measure the ping time of the
+	// connected peers again.
+	peerPingInterval = 5 * time.Minute
+
+	// peerPingTimeout is the timeout to connect a peer to measure it's
+	// ping time.
+	peerPingTimeout = 5 * time.Second
+
+	// Commands of AddNode
+	AddNodeAdd    = "add"
+	AddNodeRemove = "remove"
+	AddNodeOneTry = "onetry"
+)
+
+// PeerInfo is the status of a connected peer.
+type PeerInfo struct {
+	ID         uint64
+	Addr       string
+	Version    uint32
+	Services   uint64
+	Height     uint64
+	LastActive time.Time
+	// PingTime is the time to establish a TCP connection to the peer, zero
+	// if not measured yet
+	PingTime time.Duration
+	// FilterLoaded is the time the current transaction filter was loaded
+	// to the peer
+	FilterLoaded time.Time
+}
+
+// GetPeers returns the status of the connected peers.
+func (n *SPVNode) GetPeers() []*PeerInfo {
+	peers := n.client.PeerManager().ConnectedPeers()
+	infos := make([]*PeerInfo, 0, len(peers))
+	reloaded := n.LastFilterReload()
+	now := time.Now()
+	for _, peer := range peers {
+		info := newPeerInfo(peer)
+		// The filter is loaded to a peer on connect, and reloaded to all
+		// the connected peers later
+		var connected time.Time
+		connected, info.PingTime = n.peerStats.get(info.Addr, now)
+		info.FilterLoaded = reloaded
+		if connected.After(reloaded) {
+			info.FilterLoaded = connected
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// ConnectionCount returns the number of connected peers.
+func (n *SPVNode) ConnectionCount() int {
+	return len(n.client.PeerManager().ConnectedPeers())
+}
+
+// ReloadFilter reloads the transaction filter to the connected peers, and
+// records the time it was reloaded.
+func (n *SPVNode) ReloadFilter() {
+	n.SPVService.ReloadFilter()
+	atomic.StoreInt64(&n.filterReloaded, time.Now().Unix())
+}
+
+// LastFilterReload returns the last time the transaction filter was
+// reloaded to the connected peers, zero if not reloaded yet. Peers
+// connected later get the filter on connect.
+func (n *SPVNode) LastFilterReload() time.Time {
+	reloaded := atomic.LoadInt64(&n.filterReloaded)
+	if reloaded == 0 {
+		return time.Time{}
+	}
+	return time.Unix(reloaded, 0)
+}
+
+func newPeerInfo(peer *net.Peer) *PeerInfo {
+	return &PeerInfo{
+		ID:         peer.ID(),
+		Addr:       peer.Addr(),
+		Version:    peer.Version(),
+		Services:   peer.Services(),
+		Height:     peer.Height(),
+		LastActive: peer.LastActive(),
+	}
+}
+
+// DisconnectNode disconnects the peer by address, the address can be in
+// host:port format, or a host to disconnect all peers on it.
+func (n *SPVNode) DisconnectNode(addr string) error {
+	var disconnected bool
+	for _, peer := range n.client.PeerManager().ConnectedPeers() {
+		if peer.Addr() == addr || hostOf(peer.Addr()) == addr {
+			peer.Disconnect()
+			disconnected = true
+		}
+	}
+	if !disconnected {
+		return fmt.Errorf("peer %s not connected", addr)
+	}
+	return nil
+}
+
+// AddNode connects the peer, or removes it from the added nodes, by the
+// command "add", "remove" or "onetry". Added nodes will be reconnected
+// if disconnected.
+func (n *SPVNode) AddNode(addr, command string) error {
+	host, _, err := stdnet.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case AddNodeRemove:
+		if !n.addedNodes.remove(addr) {
+			return fmt.Errorf("node %s has not added", addr)
+		}
+		return nil
+	case AddNodeAdd, AddNodeOneTry:
+	default:
+		return fmt.Errorf("unknown command %s", command)
+	}
+
+	if n.peerStore.IsBanned(host) {
+		return fmt.Errorf("node %s is banned", host)
+	}
+	if command == AddNodeAdd && !n.addedNodes.add(addr) {
+		return fmt.Errorf("node %s has already added", addr)
+	}
+	if !n.isConnected(addr) {
+		n.client.PeerManager().ConnectPeer(addr)
+	}
+	return nil
+}
+
+// Ban bans the host until the given time and disconnects the peers on it,
+// the host can be given in host:port format.
+func (n *SPVNode) Ban(addr string, until time.Time, reason string) error {
+	host, err := normalizeHost(addr)
+	if err != nil {
+		return err
+	}
+
+	ban := &StoreBan{
+		Host:    host,
+		Created: time.Now().Unix(),
+		Until:   until.Unix(),
+		Reason:  reason,
+	}
+	if err := n.peerStore.PutBan(ban); err != nil {
+		return err
+	}
+
+	for _, peer := range n.client.PeerManager().ConnectedPeers() {
+		if hostOf(peer.Addr()) == host {
+			peer.Disconnect()
+		}
+	}
+	return nil
+}
+
+func (n *SPVNode) Unban(addr string) error {
+	host, err := normalizeHost(addr)
+	if err != nil {
+		return err
+	}
+	ok, err := n.peerStore.DeleteBan(host)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("host %s is not banned", host)
+	}
+	return nil
+}
+
+func (n *SPVNode) GetBans() ([]*StoreBan, error) {
+	return n.peerStore.GetBans()
+}
+
+func (n *SPVNode) ClearBans() error {
+	return n.peerStore.ClearBans()
+}
+
+// maintainPeers tracks the connected peers in the address book, disconnects
+// the banned peers, which may be connected again by the SDK, and reconnects
+// the added nodes periodically.
+func (n *SPVNode) maintainPeers() {
+	ticker := time.NewTicker(peerMaintainInterval)
+	defer ticker.Stop()
+
+	for {
+		var now time.Time
+		select {
+		case now = <-ticker.C:
+		case <-n.quit:
+			return
+		}
+
+		n.trackPeers(now)
+		n.pingPeers(now)
+
+		for _, peer := range n.client.PeerManager().ConnectedPeers() {
+			if n.peerStore.IsBanned(hostOf(peer.Addr())) {
+				log.Debug("[SPV_NODE] disconnect banned peer ", peer.Addr())
+				peer.Disconnect()
+			}
+		}
+		for _, addr := range n.addedNodes.list() {
+			if !n.isConnected(addr) && !n.peerStore.IsBanned(hostOf(addr)) {
+				n.client.PeerManager().ConnectPeer(addr)
+			}
+		}
+	}
+}
+
+// trackPeers updates the uptime of the connected peers in the address book.
+func (n *SPVNode) trackPeers(now time.Time) {
+	peers := n.client.PeerManager().ConnectedPeers()
+	addrs := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		addrs = append(addrs, peer.Addr())
+	}
+	n.peerStats.track(addrs, now)
+	if err := n.peerStore.TrackConnected(addrs, now); err != nil {
+		log.Error("[SPV_NODE] update address book error ", err)
+	}
+}
+
+// pingPeers measures the ping time of the connected peers not measured in
+// the ping interval, by the time to establish a TCP connection to them.
+func (n *SPVNode) pingPeers(now time.Time) {
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	pings := make(map[string]time.Duration)
+	for _, addr := range n.peerStats.pingDue(now) {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			start := time.Now()
+			conn, err := stdnet.DialTimeout("tcp", addr, peerPingTimeout)
+			if err != nil {
+				log.Debug("[SPV_NODE] ping peer ", addr, " error ", err)
+				return
+			}
+			conn.Close()
+			ping := time.Since(start)
+			n.peerStats.setPing(addr, ping, now)
+			lock.Lock()
+			pings[addr] = ping
+			lock.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+
+	if err := n.peerStore.SetPingTimes(pings); err != nil {
+		log.Error("[SPV_NODE] update address book error ", err)
+	}
+}
+
+// KnownAddrCount returns the number of addresses in the address book.
+func (n *SPVNode) KnownAddrCount() (int, error) {
+	return n.peerStore.KnownAddrCount()
+}
+
+func (n *SPVNode) isConnected(addr string) bool {
+	for _, peer := range n.client.PeerManager().ConnectedPeers() {
+		if peer.Addr() == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// bootstrapSeeds returns the seeds not banned, followed by at most count
+// addresses with highest score in the address book, so the connection
+// slots can be filled if the seeds are offline.
+func (n *SPVNode) bootstrapSeeds(seeds []string, count int) []string {
+	filtered := make([]string, 0, len(seeds)+count)
+	added := make(map[string]struct{})
+	for _, seed := range seeds {
+		if n.peerStore.IsBanned(hostOf(seed)) {
+			log.Info("Skip banned seed ", seed)
+			continue
+		}
+		filtered = append(filtered, seed)
+		added[seed] = struct{}{}
+	}
+
+	known, err := n.peerStore.GetKnownAddrs()
+	if err != nil {
+		log.Error("[SPV_NODE] load address book error ", err)
+		return filtered
+	}
+	now := time.Now()
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].Score(now) > known[j].Score(now)
+	})
+	for _, addr := range known {
+		if count == 0 {
+			break
+		}
+		if _, ok := added[addr.Addr]; ok || n.peerStore.IsBanned(hostOf(addr.Addr)) {
+			continue
+		}
+		filtered = append(filtered, addr.Addr)
+		count--
+	}
+	return filtered
+}
+
+// peerStats are the connection time and ping time of the connected peers,
+// peers are found connected by the periodical check, so the connection time
+// is the time a peer was first found connected.
+type peerStats struct {
+	sync.Mutex
+	connected map[string]time.Time
+	pings     map[string]time.Duration
+	pinged    map[string]time.Time
+}
+
+func newPeerStats() *peerStats {
+	return &peerStats{
+		connected: make(map[string]time.Time),
+		pings:     make(map[string]time.Duration),
+		pinged:    make(map[string]time.Time),
+	}
+}
+
+// track replaces the connected peers, the stats of disconnected peers are
+// removed.
+func (s *peerStats) track(addrs []string, now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	connected := make(map[string]time.Time, len(addrs))
+	for _, addr := range addrs {
+		connected[addr] = now
+		if since, ok := s.connected[addr]; ok {
+			connected[addr] = since
+		}
+	}
+	s.connected = connected
+	for addr := range s.pings {
+		if _, ok := connected[addr]; !ok {
+			delete(s.pings, addr)
+			delete(s.pinged, addr)
+		}
+	}
+}
+
+// get returns the connection time and ping time of the peer, a peer not
+// found connected before is treated as connected now.
+func (s *peerStats) get(addr string, now time.Time) (time.Time, time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	since, ok := s.connected[addr]
+	if !ok {
+		since = now
+		s.connected[addr] = now
+	}
+	return since, s.pings[addr]
+}
+
+// pingDue returns the connected peers not pinged in the ping interval.
+func (s *peerStats) pingDue(now time.Time) []string {
+	s.Lock()
+	defer s.Unlock()
+
+	var addrs []string
+	for addr := range s.connected {
+		if pinged, ok := s.pinged[addr]; !ok || now.Sub(pinged) >= peerPingInterval {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func (s *peerStats) setPing(addr string, ping time.Duration, now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	// The peer may be disconnected while pinging
+	if _, ok := s.connected[addr]; ok {
+		s.pings[addr] = ping
+		s.pinged[addr] = now
+	}
+}
+
+type addedNodes struct {
+	sync.Mutex
+	addrs map[string]struct{}
+}
+
+func newAddedNodes() *addedNodes {
+	return &addedNodes{addrs: make(map[string]struct{})}
+}
+
+func (a *addedNodes) add(addr string) bool {
+	a.Lock()
+	defer a.Unlock()
+	if _, ok := a.addrs[addr]; ok {
+		return false
+	}
+	a.addrs[addr] = struct{}{}
+	return true
+}
+
+func (a *addedNodes) remove(addr string) bool {
+	a.Lock()
+	defer a.Unlock()
+	if _, ok := a.addrs[addr]; !ok {
+		return false
+	}
+	delete(a.addrs, addr)
+	return true
+}
+
+func (a *addedNodes) list() []string {
+	a.Lock()
+	defer a.Unlock()
+	addrs := make([]string, 0, len(a.addrs))
+	for addr := range a.addrs {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// hostOf returns the host of the address in host:port format, or the
+// address itself if it has no port.
+func hostOf(addr string) string {
+	host, _, err := stdnet.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// normalizeHost returns the IP of the address in it's canonical format.
+func normalizeHost(addr string) (string, error) {
+	ip := stdnet.ParseIP(hostOf(addr))
+	if ip == nil {
+		return "", fmt.Errorf("invalid IP address %s", addr)
+	}
+	return ip.String(), nil
+}
@@ -0,0 +1,40 @@
+package node
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeerStats(t *testing.T) {
+	stats := newPeerStats()
+	start := time.Unix(1525855206, 0)
+
+	stats.track([]string{"a:1", "b:1"}, start)
+	if due := stats.pingDue(start); len(due) != 2 {
+		t.Fatalf("got %d peers to ping, expect 2", len(due))
+	}
+	stats.setPing("a:1", 10*time.Millisecond, start)
+
+	// Peers still connected keep the connection time
+	later := start.Add(peerMaintainInterval)
+	stats.track([]string{"a:1", "c:1"}, later)
+	if connected, ping := stats.get("a:1", later); connected != start || ping != 10*time.Millisecond {
+		t.Fatalf("unexpected stats of a:1 %v %v", connected, ping)
+	}
+	if connected, ping := stats.get("c:1", later); connected != later || ping != 0 {
+		t.Fatalf("unexpected stats of c:1 %v %v", connected, ping)
+	}
+	if due := stats.pingDue(later); len(due) != 1 || due[0] != "c:1" {
+		t.Fatalf("unexpected peers to ping %v", due)
+	}
+	if due := stats.pingDue(start.Add(peerPingInterval)); len(due) != 2 {
+		t.Fatalf("got %d peers to ping, expect 2", len(due))
+	}
+
+	// Disconnected peers are removed
+	stats.track(nil, later)
+	stats.setPing("a:1", time.Millisecond, later)
+	if due := stats.pingDue(later); len(due) != 0 {
+		t.Fatalf("unexpected peers to ping %v", due)
+	}
+}
@@ -0,0 +1,362 @@
+package node
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	BKTBanned   = []byte("Banned")
+	BKTAddrBook = []byte("AddrBook")
+)
+
+// MaxKnownAddrs is the maximum number of addresses in the address book, the
+// addresses with lowest score are removed when exceeded.
+const MaxKnownAddrs = 2000
+
+// PeerStore persists the banned hosts and the address book of peers, bans
+// are cached in memory to check connected peers quickly.
+type PeerStore struct {
+	*sync.RWMutex
+	*bolt.DB
+	bans map[string]*StoreBan
+	// tracked is the last time the connected addresses were tracked
+	tracked map[string]time.Time
+}
+
+func NewPeerStore() (*PeerStore, error) {
+	db, err := bolt.Open("peers.bin", 0644, &bolt.Options{InitialMmapSize: 1000000})
+	if err != nil {
+		return nil, err
+	}
+	store := &PeerStore{
+		RWMutex: new(sync.RWMutex),
+		DB:      db,
+		bans:    make(map[string]*StoreBan),
+		tracked: make(map[string]time.Time),
+	}
+
+	err = db.Update(func(btx *bolt.Tx) error {
+		_, err := btx.CreateBucketIfNotExists(BKTBanned)
+		if err != nil {
+			return err
+		}
+		_, err = btx.CreateBucketIfNotExists(BKTAddrBook)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTBanned).ForEach(func(k, v []byte) error {
+			ban := &StoreBan{Host: string(k)}
+			if err := ban.Deserialize(v); err != nil {
+				return err
+			}
+			store.bans[ban.Host] = ban
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// PutBan bans the host, replaces the existing ban of it.
+func (s *PeerStore) PutBan(ban *StoreBan) error {
+	s.Lock()
+	defer s.Unlock()
+
+	buf := new(bytes.Buffer)
+	if err := ban.Serialize(buf); err != nil {
+		return err
+	}
+	err := s.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(BKTBanned).Put([]byte(ban.Host), buf.Bytes()); err != nil {
+			return err
+		}
+		return addMisbehavior(tx.Bucket(BKTAddrBook), ban.Host)
+	})
+	if err != nil {
+		return err
+	}
+	s.bans[ban.Host] = ban
+	return nil
+}
+
+// addMisbehavior counts a ban to the addresses on the host in the address
+// book.
+func addMisbehavior(bucket *bolt.Bucket, host string) error {
+	var addrs []*KnownAddr
+	err := bucket.ForEach(func(k, v []byte) error {
+		if addrHost, err := normalizeHost(string(k)); err != nil || addrHost != host {
+			return nil
+		}
+		known := &KnownAddr{Addr: string(k)}
+		if err := known.Deserialize(v); err != nil {
+			return err
+		}
+		addrs = append(addrs, known)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, known := range addrs {
+		known.Misbehavior++
+		buf := new(bytes.Buffer)
+		if err := known.Serialize(buf); err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte(known.Addr), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteBan unbans the host, returns false if it's not banned.
+func (s *PeerStore) DeleteBan(host string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.bans[host]; !ok {
+		return false, nil
+	}
+	err := s.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTBanned).Delete([]byte(host))
+	})
+	if err != nil {
+		return false, err
+	}
+	delete(s.bans, host)
+	return true, nil
+}
+
+// GetBans returns the bans not expired yet, expired bans are removed.
+func (s *PeerStore) GetBans() ([]*StoreBan, error) {
+	if err := s.purgeBans(); err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	bans := make([]*StoreBan, 0, len(s.bans))
+	for _, ban := range s.bans {
+		bans = append(bans, ban)
+	}
+	return bans, nil
+}
+
+// ClearBans removes all the bans.
+func (s *PeerStore) ClearBans() error {
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(BKTBanned); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(BKTBanned)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.bans = make(map[string]*StoreBan)
+	return nil
+}
+
+// IsBanned returns if the host is banned and the ban has not expired.
+func (s *PeerStore) IsBanned(host string) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	ban, ok := s.bans[host]
+	return ok && !ban.Expired(time.Now())
+}
+
+func (s *PeerStore) purgeBans() error {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	var expired []string
+	for host, ban := range s.bans {
+		if ban.Expired(now) {
+			expired = append(expired, host)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	err := s.Update(func(tx *bolt.Tx) error {
+		for _, host := range expired {
+			if err := tx.Bucket(BKTBanned).Delete([]byte(host)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, host := range expired {
+		delete(s.bans, host)
+	}
+	return nil
+}
+
+// UpdateKnownAddrs updates the addresses in the address book, addresses not
+// known yet are added.
+func (s *PeerStore) UpdateKnownAddrs(addrs []string, update func(*KnownAddr)) error {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	return s.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(BKTAddrBook)
+		for _, addr := range addrs {
+			known := &KnownAddr{Addr: addr, FirstSeen: now.Unix()}
+			if data := bucket.Get([]byte(addr)); data != nil {
+				if err := known.Deserialize(data); err != nil {
+					return err
+				}
+			}
+			update(known)
+
+			buf := new(bytes.Buffer)
+			if err := known.Serialize(buf); err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(addr), buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return pruneKnownAddrs(bucket, now)
+	})
+}
+
+// TrackConnected adds the time since last tracked to the uptime of the
+// connected addresses. The time an address connected is unknown, so the
+// uptime of an address newly connected is counted from now.
+func (s *PeerStore) TrackConnected(addrs []string, now time.Time) error {
+	s.Lock()
+	since := make(map[string]time.Time, len(addrs))
+	for _, addr := range addrs {
+		since[addr] = now
+		if last, ok := s.tracked[addr]; ok {
+			since[addr] = last
+		}
+	}
+	tracked := make(map[string]time.Time, len(addrs))
+	for _, addr := range addrs {
+		tracked[addr] = now
+	}
+	s.tracked = tracked
+	s.Unlock()
+
+	if len(addrs) == 0 {
+		return nil
+	}
+	return s.UpdateKnownAddrs(addrs, func(known *KnownAddr) {
+		known.LastSeen = now.Unix()
+		known.Uptime += int64(now.Sub(since[known.Addr]) / time.Second)
+	})
+}
+
+// SetPingTimes records the measured ping times of the addresses.
+func (s *PeerStore) SetPingTimes(pings map[string]time.Duration) error {
+	if len(pings) == 0 {
+		return nil
+	}
+	addrs := make([]string, 0, len(pings))
+	for addr := range pings {
+		addrs = append(addrs, addr)
+	}
+	return s.UpdateKnownAddrs(addrs, func(known *KnownAddr) {
+		known.PingTime = int64(pings[known.Addr] / time.Millisecond)
+	})
+}
+
+// KnownAddrCount returns the number of addresses in the address book.
+func (s *PeerStore) KnownAddrCount() (count int, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	err = s.View(func(tx *bolt.Tx) error {
+		count = tx.Bucket(BKTAddrBook).Stats().KeyN
+		return nil
+	})
+	return count, err
+}
+
+// GetKnownAddrs returns the addresses in the address book.
+func (s *PeerStore) GetKnownAddrs() (addrs []*KnownAddr, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	err = s.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTAddrBook).ForEach(func(k, v []byte) error {
+			known := &KnownAddr{Addr: string(k)}
+			if err := known.Deserialize(v); err != nil {
+				return err
+			}
+			addrs = append(addrs, known)
+			return nil
+		})
+	})
+	return addrs, err
+}
+
+// pruneKnownAddrs removes the addresses with lowest score exceeded the
+// capacity of the address book.
+func pruneKnownAddrs(bucket *bolt.Bucket, now time.Time) error {
+	// Count the keys only, addresses are decoded if exceeded
+	var count int
+	cursor := bucket.Cursor()
+	for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+		count++
+	}
+	if count <= MaxKnownAddrs {
+		return nil
+	}
+
+	addrs := make([]*KnownAddr, 0, count)
+	err := bucket.ForEach(func(k, v []byte) error {
+		known := &KnownAddr{Addr: string(k)}
+		if err := known.Deserialize(v); err != nil {
+			return err
+		}
+		addrs = append(addrs, known)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Score(now) < addrs[j].Score(now)
+	})
+	for _, known := range addrs[:len(addrs)-MaxKnownAddrs] {
+		if err := bucket.Delete([]byte(known.Addr)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PeerStore) Close() {
+	s.Lock()
+	s.DB.Close()
+}
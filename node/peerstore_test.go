@@ -0,0 +1,268 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// openTestPeerStore opens a peer store in a temporary directory, the
+// returned function removes the directory after the store closed.
+func openTestPeerStore(t *testing.T) (*PeerStore, func()) {
+	cleanup := useTempDir(t)
+	peers, err := NewPeerStore()
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return peers, cleanup
+}
+
+func TestStoreBanSerialize(t *testing.T) {
+	ban := &StoreBan{Host: "10.0.0.1", Created: 1525854988, Until: 1525941388, Reason: "manually added"}
+	buf := new(bytes.Buffer)
+	if err := ban.Serialize(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &StoreBan{Host: ban.Host}
+	if err := decoded.Deserialize(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if *decoded != *ban {
+		t.Fatalf("ban deserialized as %v, expect %v", decoded, ban)
+	}
+	if err := decoded.Deserialize(buf.Bytes()[:10]); err == nil {
+		t.Fatal("truncated ban deserialized")
+	}
+}
+
+func TestPeerStoreBans(t *testing.T) {
+	peers, cleanup := openTestPeerStore(t)
+	defer cleanup()
+	defer func() { peers.Close() }()
+
+	now := time.Now()
+	bans := []*StoreBan{
+		{Host: "10.0.0.1", Created: now.Unix(), Until: now.Add(time.Hour).Unix(), Reason: "first"},
+		{Host: "10.0.0.2", Created: now.Unix(), Until: now.Add(time.Hour).Unix(), Reason: "second"},
+		{Host: "10.0.0.3", Created: now.Unix(), Until: now.Add(-time.Second).Unix(), Reason: "expired"},
+	}
+	for _, ban := range bans {
+		if err := peers.PutBan(ban); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !peers.IsBanned("10.0.0.1") || peers.IsBanned("10.0.0.3") || peers.IsBanned("10.0.0.4") {
+		t.Fatal("unexpected banned hosts")
+	}
+
+	// Bans are persisted across restarts
+	peers.Close()
+	var err error
+	if peers, err = NewPeerStore(); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := peers.GetBans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("got %d bans, expect 2", len(stored))
+	}
+	for _, ban := range stored {
+		if ban.Host == "10.0.0.1" && *ban != *bans[0] {
+			t.Fatalf("ban reloaded as %v, expect %v", ban, bans[0])
+		}
+	}
+
+	if ok, err := peers.DeleteBan("10.0.0.1"); !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	if ok, _ := peers.DeleteBan("10.0.0.1"); ok {
+		t.Fatal("unbanned host deleted again")
+	}
+	if peers.IsBanned("10.0.0.1") {
+		t.Fatal("host still banned after deleted")
+	}
+
+	if err := peers.ClearBans(); err != nil {
+		t.Fatal(err)
+	}
+	peers.Close()
+	if peers, err = NewPeerStore(); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := peers.GetBans(); len(stored) != 0 || peers.IsBanned("10.0.0.2") {
+		t.Fatal("bans not cleared")
+	}
+}
+
+func TestKnownAddrScore(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		addr  KnownAddr
+		score float64
+	}{
+		{KnownAddr{Uptime: 7200, LastSeen: now.Unix()}, 2},
+		{KnownAddr{Uptime: 30 * 86400, LastSeen: now.Unix()}, 24},
+		{KnownAddr{Uptime: 7200, LastSeen: now.Add(-48 * time.Hour).Unix()}, 0},
+		{KnownAddr{LastSeen: now.Add(time.Hour).Unix()}, 0},
+		{KnownAddr{Uptime: 7200, LastSeen: now.Unix(), PingTime: 150}, 0.5},
+		{KnownAddr{Uptime: 7200, LastSeen: now.Unix(), PingTime: 5000}, -8},
+		{KnownAddr{Uptime: 30 * 86400, LastSeen: now.Unix(), Misbehavior: 2}, 4},
+	}
+	for _, c := range cases {
+		if score := c.addr.Score(now); score != c.score {
+			t.Fatalf("address %v score %v, expect %v", c.addr, score, c.score)
+		}
+	}
+}
+
+func TestPeerStoreKnownAddrs(t *testing.T) {
+	peers, cleanup := openTestPeerStore(t)
+	defer cleanup()
+	defer func() { peers.Close() }()
+
+	// The address book is filled with addresses seen now, and one seen a
+	// day ago with the lowest score
+	now := time.Now()
+	addrs := make([]string, 0, MaxKnownAddrs+1)
+	for i := 0; i < MaxKnownAddrs; i++ {
+		addrs = append(addrs, fmt.Sprintf("10.0.%d.%d:20338", i/256, i%256))
+	}
+	stale := "10.1.0.1:20338"
+	addrs = append(addrs, stale)
+	err := peers.UpdateKnownAddrs(addrs, func(known *KnownAddr) {
+		known.LastSeen = now.Unix()
+		if known.Addr == stale {
+			known.LastSeen = now.Add(-24 * time.Hour).Unix()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := peers.KnownAddrCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != MaxKnownAddrs {
+		t.Fatalf("got %d known addresses, expect %d", count, MaxKnownAddrs)
+	}
+	known, err := peers.GetKnownAddrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range known {
+		if addr.Addr == stale {
+			t.Fatal("lowest scored address not pruned")
+		}
+	}
+}
+
+func TestPeerStoreTrackConnected(t *testing.T) {
+	peers, cleanup := openTestPeerStore(t)
+	defer cleanup()
+	defer func() { peers.Close() }()
+
+	uptime := func(addr string) int64 {
+		addrs, err := peers.GetKnownAddrs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, known := range addrs {
+			if known.Addr == addr {
+				return known.Uptime
+			}
+		}
+		t.Fatalf("address %s not known", addr)
+		return 0
+	}
+	track := func(now time.Time, addrs ...string) {
+		if err := peers.TrackConnected(addrs, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const first, second = "10.0.0.1:20338", "10.0.0.2:20338"
+	now := time.Now()
+	track(now, first)
+	if uptime(first) != 0 {
+		t.Fatal("newly connected address has uptime")
+	}
+
+	// Only the time connected is counted
+	track(now.Add(30*time.Second), first, second)
+	if uptime(first) != 30 || uptime(second) != 0 {
+		t.Fatalf("uptime %d and %d, expect 30 and 0", uptime(first), uptime(second))
+	}
+	track(now.Add(60*time.Second), second)
+	track(now.Add(90*time.Second), first, second)
+	if uptime(first) != 30 || uptime(second) != 60 {
+		t.Fatalf("uptime %d and %d, expect 30 and 60", uptime(first), uptime(second))
+	}
+}
+
+func TestKnownAddrSerialize(t *testing.T) {
+	known := &KnownAddr{Addr: "10.0.0.1:20338", FirstSeen: 1525854988, LastSeen: 1525941388,
+		Uptime: 3600, PingTime: 120, Misbehavior: 1}
+	buf := new(bytes.Buffer)
+	if err := known.Serialize(buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded := &KnownAddr{Addr: known.Addr}
+	if err := decoded.Deserialize(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if *decoded != *known {
+		t.Fatalf("address deserialized as %v, expect %v", decoded, known)
+	}
+
+	// Addresses stored without ping time and misbehavior
+	decoded = &KnownAddr{Addr: known.Addr}
+	if err := decoded.Deserialize(buf.Bytes()[:24]); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Uptime != known.Uptime || decoded.PingTime != 0 || decoded.Misbehavior != 0 {
+		t.Fatalf("unexpected address deserialized %v", decoded)
+	}
+}
+
+func TestPeerStoreMisbehavior(t *testing.T) {
+	peers, cleanup := openTestPeerStore(t)
+	defer cleanup()
+	defer func() { peers.Close() }()
+
+	const banned, other = "10.0.0.1:20338", "10.0.0.2:20338"
+	now := time.Now()
+	if err := peers.TrackConnected([]string{banned, other}, now); err != nil {
+		t.Fatal(err)
+	}
+	err := peers.SetPingTimes(map[string]time.Duration{other: 80 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ban := &StoreBan{Host: "10.0.0.1", Created: now.Unix(), Until: now.Add(time.Hour).Unix()}
+	if err := peers.PutBan(ban); err != nil {
+		t.Fatal(err)
+	}
+
+	known, err := peers.GetKnownAddrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range known {
+		switch addr.Addr {
+		case banned:
+			if addr.Misbehavior != 1 || addr.PingTime != 0 {
+				t.Fatalf("unexpected banned address %v", addr)
+			}
+		case other:
+			if addr.Misbehavior != 0 || addr.PingTime != 80 {
+				t.Fatalf("unexpected address %v", addr)
+			}
+		}
+	}
+}
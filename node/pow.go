@@ -0,0 +1,87 @@
+package node
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+// DefaultPowLimitBits is the proof of work limit of ELA main chain in
+// compact form, the difficulty of a block is relative to it.
+const DefaultPowLimitBits = 0x1f0008ff
+
+// CompactToBig converts a compact representation of a whole number to an
+// unsigned 256-bit number, which is the format of the Bits in block header.
+func CompactToBig(compact uint32) *big.Int {
+	// Extract the mantissa, sign bit, and exponent.
+	mantissa := compact & 0x007fffff
+	isNegative := compact&0x00800000 != 0
+	exponent := uint(compact >> 24)
+
+	var bn *big.Int
+	if exponent <= 3 {
+		mantissa >>= 8 * (3 - exponent)
+		bn = big.NewInt(int64(mantissa))
+	} else {
+		bn = big.NewInt(int64(mantissa))
+		bn.Lsh(bn, 8*(exponent-3))
+	}
+
+	if isNegative {
+		bn = bn.Neg(bn)
+	}
+	return bn
+}
+
+// HashToBig converts a hash into a big integer, the hash is in little-endian
+// byte order.
+func HashToBig(hash *common.Uint256) *big.Int {
+	var buf [32]byte
+	for i := range buf {
+		buf[i] = hash[len(hash)-1-i]
+	}
+	return new(big.Int).SetBytes(buf[:])
+}
+
+// powLimit returns the proof of work limit target.
+func powLimit() *big.Int {
+	powLimitBits := config.Values().PowLimitBits
+	if powLimitBits == 0 {
+		powLimitBits = DefaultPowLimitBits
+	}
+	return CompactToBig(powLimitBits)
+}
+
+// CalcDifficulty returns the difficulty of the Bits, which is the multiple
+// of the proof of work limit target to the target of the Bits.
+func CalcDifficulty(bits uint32) *big.Float {
+	target := CompactToBig(bits)
+	if target.Sign() <= 0 {
+		return new(big.Float)
+	}
+	limit := new(big.Float).SetInt(powLimit())
+	return limit.Quo(limit, new(big.Float).SetInt(target))
+}
+
+// CheckProofOfWork checks the target of the header Bits is within the proof
+// of work limit, and the hash of the parent block merge mined the header is
+// not higher than the target.
+func CheckProofOfWork(header *core.Header) error {
+	target := CompactToBig(header.Bits)
+	if target.Sign() <= 0 {
+		return errors.New("block target difficulty is too low")
+	}
+	if target.Cmp(powLimit()) > 0 {
+		return errors.New("block target difficulty is higher than max of limit")
+	}
+
+	hash := header.AuxPow.ParBlockHeader.Hash()
+	if HashToBig(&hash).Cmp(target) > 0 {
+		return errors.New("block target difficulty is higher than expected difficulty")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+// testPowLimitBits is the proof of work limit of the test headers, so they
+// can be mined quickly.
+const testPowLimitBits = 0x207fffff
+
+// useTestPowLimit lowers the proof of work limit, the returned function
+// restores it.
+func useTestPowLimit() func() {
+	powLimitBits := config.Values().PowLimitBits
+	config.Values().PowLimitBits = testPowLimitBits
+	return func() { config.Values().PowLimitBits = powLimitBits }
+}
+
+// mineTestHeader sets the Bits of the header to the test proof of work
+// limit and finds the parent block nonce satisfies it.
+func mineTestHeader(t *testing.T, header *core.Header) {
+	header.Bits = testPowLimitBits
+	for nonce := uint32(0); nonce < 100; nonce++ {
+		header.AuxPow.ParBlockHeader.Nonce = nonce
+		if CheckProofOfWork(header) == nil {
+			return
+		}
+	}
+	t.Fatal("mine test header failed")
+}
+
+func TestCalcDifficulty(t *testing.T) {
+	cases := []struct {
+		bits       uint32
+		difficulty string
+	}{
+		{DefaultPowLimitBits, "1.00000000"},
+		{0x1f00047f, "2.00086881"},
+		{0x1f0005ff, "1.50032573"},
+		{0x1e0008ff, "256.00000000"},
+		{0, "0.00000000"},
+	}
+	for _, c := range cases {
+		if difficulty := CalcDifficulty(c.bits).Text('f', 8); difficulty != c.difficulty {
+			t.Fatalf("bits %x difficulty %s, expect %s", c.bits, difficulty, c.difficulty)
+		}
+	}
+}
+
+func TestCheckProofOfWork(t *testing.T) {
+	defer useTestPowLimit()()
+
+	header := &core.Header{Height: 1}
+	mineTestHeader(t, header)
+
+	// Bits above the limit are rejected even the hash satisfies them
+	header.Bits = 0x217fffff
+	if CheckProofOfWork(header) == nil {
+		t.Fatal("target above the limit accepted")
+	}
+	header.Bits = 0
+	if CheckProofOfWork(header) == nil {
+		t.Fatal("zero target accepted")
+	}
+
+	// A hash higher than the target is rejected
+	header.Bits = 0x1f0008ff
+	for nonce := uint32(0); ; nonce++ {
+		header.AuxPow.ParBlockHeader.Nonce = nonce
+		hash := header.AuxPow.ParBlockHeader.Hash()
+		if HashToBig(&hash).Cmp(CompactToBig(header.Bits)) > 0 {
+			break
+		}
+	}
+	if CheckProofOfWork(header) == nil {
+		t.Fatal("hash higher than the target accepted")
+	}
+}
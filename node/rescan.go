@@ -0,0 +1,151 @@
+package node
+
+import (
+	"sync"
+
+	"github.com/elastos/Elastos.ELA.SPV/store"
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+// RescanInfo describes the progress of a rescan.
+type RescanInfo struct {
+	InProgress    bool
+	StartHeight   uint32
+	CurrentHeight uint32
+	TargetHeight  uint32
+	Addrs         []*common.Uint168
+}
+
+type rescanner struct {
+	sync.RWMutex
+	inProgress bool
+	start      uint32
+	current    uint32
+	target     uint32
+	addrs      map[common.Uint168]struct{}
+
+	// tip is the best header before the rescan, it is reported as the
+	// chain tip until the rescan reached it
+	tip *store.StoreHeader
+}
+
+func (r *rescanner) begin(start uint32, tip *store.StoreHeader, addrs []*common.Uint168) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.inProgress {
+		return false
+	}
+
+	r.inProgress = true
+	r.start = start
+	r.current = start - 1
+	r.target = tip.Height
+	r.tip = tip
+	r.addrs = nil
+	if len(addrs) > 0 {
+		r.addrs = make(map[common.Uint168]struct{})
+		for _, addr := range addrs {
+			r.addrs[*addr] = struct{}{}
+		}
+	}
+	return true
+}
+
+// update the rescan progress with the committed block height, returns true
+// when the rescan reached the target height.
+func (r *rescanner) update(height uint32) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.inProgress || height < r.start {
+		return false
+	}
+
+	r.current = height
+	if r.current >= r.target {
+		r.inProgress = false
+		r.addrs = nil
+		return true
+	}
+	return false
+}
+
+func (r *rescanner) abort() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.inProgress = false
+	r.addrs = nil
+}
+
+// bestHeader returns the best header before the rescan in progress, nil if
+// no rescan is in progress.
+func (r *rescanner) bestHeader() *store.StoreHeader {
+	r.RLock()
+	defer r.RUnlock()
+
+	if !r.inProgress {
+		return nil
+	}
+	return r.tip
+}
+
+// covers checks if the block on height is synchronized again by the rescan
+// in progress.
+func (r *rescanner) covers(height uint32) bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.inProgress && height >= r.start && height <= r.target
+}
+
+// filtered returns the address subset of the rescan in progress which
+// covers the given height, nil means no restriction.
+func (r *rescanner) filtered(height uint32) map[common.Uint168]struct{} {
+	r.RLock()
+	defer r.RUnlock()
+
+	if !r.inProgress || height > r.target {
+		return nil
+	}
+	return r.addrs
+}
+
+func (r *rescanner) info() *RescanInfo {
+	r.RLock()
+	defer r.RUnlock()
+
+	info := &RescanInfo{
+		InProgress:    r.inProgress,
+		StartHeight:   r.start,
+		CurrentHeight: r.current,
+		TargetHeight:  r.target,
+	}
+	for addr := range r.addrs {
+		hash := addr
+		info.Addrs = append(info.Addrs, &hash)
+	}
+	return info
+}
+
+// TxMatches checks if the transaction pays to or spends from any of the
+// addresses.
+func (n *SPVNode) TxMatches(tx *core.Transaction, addrs map[common.Uint168]struct{}) bool {
+	for _, output := range tx.Outputs {
+		if _, ok := addrs[output.ProgramHash]; ok {
+			return true
+		}
+	}
+	for _, input := range tx.Inputs {
+		utxo, err := n.DataStore.GetOutput(&input.Previous)
+		if err != nil || utxo == nil {
+			continue
+		}
+		if _, ok := addrs[utxo.ProgramHash]; ok {
+			return true
+		}
+	}
+	return false
+}
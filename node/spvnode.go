@@ -4,30 +4,64 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/elastos/Elastos.ELA.SPV.Node/config"
 	"github.com/elastos/Elastos.ELA.SPV/log"
 	"github.com/elastos/Elastos.ELA.SPV/sdk"
+	"github.com/elastos/Elastos.ELA.SPV/store"
 
 	"github.com/elastos/Elastos.ELA.Utility/common"
 	"github.com/elastos/Elastos.ELA.Utility/p2p/msg"
 	"github.com/elastos/Elastos.ELA/core"
 )
 
-const MaxConnections = 10
+const (
+	// DefaultMaxConnections is the maximum number of peers to connect if
+	// not specified in config.
+	DefaultMaxConnections = 10
+
+	// BirthdayTimeThreshold is the number below which an address birthday
+	// is interpreted as a block height, otherwise as an unix timestamp.
+	BirthdayTimeThreshold = 500000000
+
+	// birthdayMargin is the number of blocks ahead of the best height an
+	// address is put into the transaction filter before it's birthday,
+	// so the merkle blocks requested in advance will not miss it.
+	birthdayMargin = 1000
+)
 
 var AssetEla = getElaId()
 
 type SPVNode struct {
+	// filterReloaded is accessed atomically, keep it 64-bit aligned
+	filterReloaded int64
 	sdk.SPVService
 	*HeaderStore
 	*DataStore
-	waitChan chan byte
+	peerStore  *PeerStore
+	peerStats  *peerStats
+	addedNodes *addedNodes
+	client     sdk.SPVClient
+	quit       chan struct{}
+	stopOnce   sync.Once
+	waitChan   chan byte
+	rescan     *rescanner
+	bornLock   sync.Mutex
+	bornAddrs  map[common.Uint168]struct{}
+	state      int32
+	listeners  *listeners
 }
 
 func NewSpvNode(seeds []string) (*SPVNode, error) {
 	var err error
 	node := new(SPVNode)
+	node.rescan = new(rescanner)
+	node.listeners = new(listeners)
 	node.HeaderStore, err = NewHeaderStore()
 	if err != nil {
 		return nil, err
@@ -38,20 +72,29 @@ func NewSpvNode(seeds []string) (*SPVNode, error) {
 		return nil, err
 	}
 
+	node.peerStore, err = NewPeerStore()
+	if err != nil {
+		return nil, err
+	}
+	node.peerStats = newPeerStats()
+	node.addedNodes = newAddedNodes()
+	node.quit = make(chan struct{})
+
 	var clientId [8]byte
 	rand.Read(clientId[:])
-	spvClient, err := sdk.GetSPVClient(
+	maxConnections := MaxConnections()
+	node.client, err = sdk.GetSPVClient(
 		config.Values().Magic,
 		binary.LittleEndian.Uint64(clientId[:]),
-		seeds,
-		MaxConnections,
-		MaxConnections,
+		node.bootstrapSeeds(seeds, maxConnections),
+		maxConnections,
+		maxConnections,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	node.SPVService, err = sdk.GetSPVService(spvClient, node.HeaderStore, node)
+	node.SPVService, err = sdk.GetSPVService(node.client, node.HeaderStore, node)
 	if err != nil {
 		return nil, err
 	}
@@ -59,28 +102,150 @@ func NewSpvNode(seeds []string) (*SPVNode, error) {
 	return node, err
 }
 
+// MaxConnections returns the maximum number of peers to connect.
+func MaxConnections() int {
+	if max := config.Values().MaxConnections; max > 0 {
+		return max
+	}
+	return DefaultMaxConnections
+}
+
 func (n *SPVNode) GetData() ([]*common.Uint168, []*core.OutPoint) {
 	ops, err := n.DataStore.GetOps()
 	if err != nil {
 		log.Error("[SPV_NODE] GetData error ", err)
 	}
 
-	return n.DataStore.GetAddrs(), ops
+	// Addresses not born yet do not need to be filtered
+	addrs := n.DataStore.GetBornAddrs(n.BestHeight() + birthdayMargin)
+	n.updateBornAddrs(addrs)
+
+	return addrs, ops
+}
+
+// updateBornAddrs replaces the addresses put into the filter, returns true
+// if they changed.
+func (n *SPVNode) updateBornAddrs(addrs []*common.Uint168) bool {
+	n.bornLock.Lock()
+	defer n.bornLock.Unlock()
+
+	changed := len(addrs) != len(n.bornAddrs)
+	for _, addr := range addrs {
+		if _, ok := n.bornAddrs[*addr]; !ok {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return false
+	}
+
+	n.bornAddrs = make(map[common.Uint168]struct{}, len(addrs))
+	for _, addr := range addrs {
+		n.bornAddrs[*addr] = struct{}{}
+	}
+	return true
+}
+
+func (n *SPVNode) OnStateChange(state sdk.ChainState) {
+	atomic.StoreInt32(&n.state, int32(state))
+	n.listeners.notifyStateChange(state)
 }
 
-func (n *SPVNode) OnStateChange(sdk.ChainState) {}
+// ChainState returns the latest synchronization state of the chain.
+func (n *SPVNode) ChainState() sdk.ChainState {
+	return sdk.ChainState(atomic.LoadInt32(&n.state))
+}
+
+// EstimatedHeight returns the estimated height of the network chain tip,
+// which is the highest height reported by the connected peers, or the best
+// height if no peer is higher.
+func (n *SPVNode) EstimatedHeight() uint32 {
+	height := n.BestHeight()
+	for _, peer := range n.client.PeerManager().ConnectedPeers() {
+		if peerHeight := uint32(peer.Height()); peerHeight > height {
+			height = peerHeight
+		}
+	}
+	return height
+}
 
 func (n *SPVNode) CommitTx(tx *core.Transaction, height uint32) (bool, error) {
-	return n.DataStore.PutTx(NewStoreTx(tx, height))
+	// Only commit transactions corresponding with the rescan addresses
+	// until the rescan finished, others are false positives only if they
+	// do not match the other registered addresses either
+	if addrs := n.rescan.filtered(height); addrs != nil && !n.TxMatches(tx, addrs) {
+		return !n.DataStore.MatchesFilter(tx), nil
+	}
+
+	exist := n.DataStore.HasTx(tx.Hash())
+	fPositive, err := n.DataStore.PutTx(NewStoreTx(tx, height))
+	if err == nil && !fPositive && !exist {
+		n.listeners.addPending(tx)
+	}
+	return fPositive, err
 }
 
-func (n *SPVNode) OnBlockCommitted(*msg.MerkleBlock, []*core.Transaction) {}
+func (n *SPVNode) OnBlockCommitted(block *msg.MerkleBlock, txs []*core.Transaction) {
+	header, ok := block.Header.(*core.Header)
+	if !ok {
+		return
+	}
+	// The transactions of a merkle block not matching it's header can not
+	// be proved, they are removed before notified
+	proofs, err := NewMerkleProofs(block)
+	if err != nil {
+		log.Error("[SPV_NODE] invalid merkle block at height ", header.Height, ", ", err)
+		n.listeners.dropPending()
+		if err := n.DataStore.Rollback(header.Height); err != nil {
+			log.Error("[SPV_NODE] remove transactions at height ", header.Height, " error ", err)
+		}
+	} else if len(proofs) > 0 {
+		if err := n.DataStore.PutMerkleProofs(proofs); err != nil {
+			log.Error("[SPV_NODE] store merkle proofs error ", err)
+		}
+	}
+
+	// Blocks synchronized again by rescan have been notified before, only
+	// the transactions newly found in them are notified
+	n.listeners.notifyBlock(header, n.rescan.covers(header.Height))
+
+	if n.rescan.update(header.Height) {
+		log.Info("Rescan finished at height ", header.Height)
+	}
+
+	// Reload filter when new addresses reached their birthday, the addresses
+	// are replaced first so the filter is reloaded once
+	if n.updateBornAddrs(n.DataStore.GetBornAddrs(header.Height + birthdayMargin)) {
+		go n.ReloadFilter()
+	}
+}
 
 func (n *SPVNode) OnRollback(height uint32) error {
-	return n.DataStore.Rollback(height)
+	var txs []*core.Transaction
+	txIds, err := n.DataStore.GetTxIds(height)
+	if err != nil {
+		return err
+	}
+	for _, txId := range txIds {
+		tx, err := n.DataStore.GetTx(txId)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, &tx.Transaction)
+	}
+
+	if err := n.DataStore.Rollback(height); err != nil {
+		return err
+	}
+
+	n.listeners.notifyRollback(height, txs)
+	return nil
 }
 
 func (n *SPVNode) Start() {
+	go n.maintainPeers()
+
 	log.Debug("Wait for register addresses...")
 	n.waitChan = make(chan byte)
 	<-n.waitChan
@@ -88,20 +253,31 @@ func (n *SPVNode) Start() {
 	n.waitChan = nil
 
 	n.SPVService.Start()
+	atomic.StoreInt64(&n.filterReloaded, time.Now().Unix())
 }
 
+// Stop stops the node and closes the stores, it can be called more than
+// once and only the first call takes effect.
 func (n *SPVNode) Stop() {
-	if n.waitChan != nil {
-		close(n.waitChan)
-	}
-	n.DataStore.Close()
-	n.SPVService.Stop()
+	n.stopOnce.Do(func() {
+		if n.waitChan != nil {
+			close(n.waitChan)
+		}
+		close(n.quit)
+		n.DataStore.Close()
+		n.SPVService.Stop()
+		n.peerStore.Close()
+	})
 }
 
 // Interface implements
-func (n *SPVNode) RegisterAddresses(addresses []string) error {
+func (n *SPVNode) RegisterAddresses(addresses []string, birthday uint32) error {
 	for _, address := range addresses {
-		if _, err := n.DataStore.PutAddr(address); err != nil {
+		addr, err := n.newStoreAddr(address, "", birthday)
+		if err != nil {
+			return err
+		}
+		if _, err := n.DataStore.PutAddr(addr); err != nil {
 			return err
 		}
 	}
@@ -113,26 +289,158 @@ func (n *SPVNode) RegisterAddresses(addresses []string) error {
 	return nil
 }
 
-func (n *SPVNode) RegisterAddress(address string) error {
-	ok, err := n.DataStore.PutAddr(address)
+func (n *SPVNode) RegisterAddress(address, label string, birthday uint32) error {
+	addr, err := n.newStoreAddr(address, label, birthday)
+	if err != nil {
+		return err
+	}
+	ok, err := n.DataStore.PutAddr(addr)
 	if err != nil {
 		return err
 	}
 	if !ok {
 		return errors.New("address has already registered")
 	}
-	n.SPVService.ReloadFilter()
+	n.ReloadFilter()
+
+	// Synchronize historical transactions since the address birthday, the
+	// registration is reverted if the rescan can not start, so it can be
+	// registered again later
+	if addr.Birthday > 0 && addr.Birthday <= n.BestHeight() {
+		if err := n.Rescan(addr.Birthday, []string{address}); err != nil {
+			if _, err := n.DataStore.DeleteAddr(address, true); err != nil {
+				log.Error("[SPV_NODE] revert address ", address, " error ", err)
+			}
+			n.ReloadFilter()
+			return fmt.Errorf("rescan from birthday %d failed, %s", addr.Birthday, err)
+		}
+	}
 	return nil
 }
 
-func (n *SPVNode) BestHeight() uint32 {
+func (n *SPVNode) UnregisterAddresses(addresses []string, purge bool) error {
+	if err := n.DataStore.DeleteAddrs(addresses, purge); err != nil {
+		return err
+	}
+	n.ReloadFilter()
+	return nil
+}
+
+func (n *SPVNode) UnregisterAddress(address string, purge bool) error {
+	ok, err := n.DataStore.DeleteAddr(address, purge)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("address has not registered")
+	}
+	n.ReloadFilter()
+	return nil
+}
+
+// Rescan synchronize the blocks again from the start height, to get the
+// historical transactions of addresses registered after they were used.
+// If addresses are given, only transactions corresponding with them will
+// be committed during the rescan.
+func (n *SPVNode) Rescan(start uint32, addresses []string) error {
+	addrs := make([]*common.Uint168, 0, len(addresses))
+	for _, address := range addresses {
+		hash, err := common.Uint168FromAddress(address)
+		if err != nil {
+			return err
+		}
+		if !n.DataStore.ContainAddr(hash) {
+			return fmt.Errorf("address %s has not registered", address)
+		}
+		addrs = append(addrs, hash)
+	}
+
+	// Start from the earliest birthday of the addresses by default
+	if start == 0 {
+		start = n.earliestBirthday(addrs)
+	}
+	if start == 0 {
+		start = 1
+	}
+
+	tip, err := n.GetBestHeader()
+	if err != nil {
+		return err
+	}
+	if start > tip.Height {
+		return fmt.Errorf("start height %d is higher than best height %d", start, tip.Height)
+	}
+
+	if !n.rescan.begin(start, tip, addrs) {
+		return errors.New("another rescan is in progress")
+	}
+
+	if err := n.HeaderStore.Rewind(start - 1); err != nil {
+		n.rescan.abort()
+		return err
+	}
+	log.Info("Rescan from height ", start, " to ", tip.Height)
+	n.ReloadFilter()
+	return nil
+}
+
+func (n *SPVNode) RescanInfo() *RescanInfo {
+	return n.rescan.info()
+}
+
+// GetBestHeader returns the chain tip, the header store is rewound during
+// rescan, so the best header before the rescan is returned until the rescan
+// reached it.
+func (n *SPVNode) GetBestHeader() (*store.StoreHeader, error) {
 	tip, err := n.HeaderStore.GetBestHeader()
+	if best := n.rescan.bestHeader(); best != nil && (err != nil || best.Height > tip.Height) {
+		return best, nil
+	}
+	return tip, err
+}
+
+func (n *SPVNode) BestHeight() uint32 {
+	tip, err := n.GetBestHeader()
 	if err != nil {
 		return 0
 	}
 	return tip.Height
 }
 
+func (n *SPVNode) earliestBirthday(addrs []*common.Uint168) uint32 {
+	if len(addrs) == 0 {
+		addrs = n.DataStore.GetAddrs()
+	}
+
+	var earliest uint32 = math.MaxUint32
+	for _, addr := range addrs {
+		if birthday := n.DataStore.GetBirthday(addr); birthday < earliest {
+			earliest = birthday
+		}
+	}
+	return earliest
+}
+
+func (n *SPVNode) newStoreAddr(address, label string, birthday uint32) (*StoreAddr, error) {
+	addr, err := NewStoreAddr(address)
+	if err != nil {
+		return nil, err
+	}
+	addr.Time = time.Now().Unix()
+	addr.Height = n.BestHeight()
+	addr.Label = label
+	addr.Birthday = birthday
+	if birthday >= BirthdayTimeThreshold {
+		addr.Birthday, err = n.HeaderStore.GetHeightByTime(birthday)
+		if err != nil {
+			// Can not resolve the birthday, synchronize from genesis to be safe
+			log.Warn("Resolve birthday of ", address, " failed, ", err)
+			addr.Birthday = 0
+		}
+	}
+	return addr, nil
+}
+
 func getElaId() common.Uint256 {
 	// ELA coin
 	elaCoin := &core.Transaction{
@@ -0,0 +1,71 @@
+package node
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+type StoreAddr struct {
+	Address  string
+	Hash     common.Uint168
+	Time     int64
+	Height   uint32
+	Label    string
+	Birthday uint32
+}
+
+func NewStoreAddr(address string) (*StoreAddr, error) {
+	hash, err := common.Uint168FromAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return &StoreAddr{Address: address, Hash: *hash}, nil
+}
+
+// Serialize the address info, the address string is not included because
+// it is used as the key in database.
+func (a *StoreAddr) Serialize(buf io.Writer) error {
+	if err := a.Hash.Serialize(buf); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.LittleEndian, a.Time); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.LittleEndian, a.Height); err != nil {
+		return err
+	}
+	if err := common.WriteVarString(buf, a.Label); err != nil {
+		return err
+	}
+	return binary.Write(buf, binary.LittleEndian, a.Birthday)
+}
+
+func (a *StoreAddr) Deserialize(data []byte) error {
+	reader := bytes.NewReader(data)
+	if err := a.Hash.Deserialize(reader); err != nil {
+		return err
+	}
+	// Addresses registered by early versions only have the program hash stored
+	if reader.Len() == 0 {
+		return nil
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &a.Time); err != nil {
+		return err
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &a.Height); err != nil {
+		return err
+	}
+	label, err := common.ReadVarString(reader)
+	if err != nil {
+		return err
+	}
+	a.Label = label
+	// Birthday unknown
+	if reader.Len() == 0 {
+		return nil
+	}
+	return binary.Read(reader, binary.LittleEndian, &a.Birthday)
+}
@@ -0,0 +1,50 @@
+package node
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+type StoreBan struct {
+	Host    string
+	Created int64
+	Until   int64
+	Reason  string
+}
+
+// Expired returns if the ban has expired at the given time.
+func (b *StoreBan) Expired(now time.Time) bool {
+	return now.Unix() >= b.Until
+}
+
+// Serialize the ban info, the host is not included because it is used as
+// the key in database.
+func (b *StoreBan) Serialize(buf io.Writer) error {
+	if err := binary.Write(buf, binary.LittleEndian, b.Created); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.LittleEndian, b.Until); err != nil {
+		return err
+	}
+	return common.WriteVarString(buf, b.Reason)
+}
+
+func (b *StoreBan) Deserialize(data []byte) error {
+	reader := bytes.NewReader(data)
+	if err := binary.Read(reader, binary.LittleEndian, &b.Created); err != nil {
+		return err
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &b.Until); err != nil {
+		return err
+	}
+	reason, err := common.ReadVarString(reader)
+	if err != nil {
+		return err
+	}
+	b.Reason = reason
+	return nil
+}
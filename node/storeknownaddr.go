@@ -0,0 +1,74 @@
+package node
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// KnownAddr is a peer address in the address book.
+type KnownAddr struct {
+	Addr      string
+	FirstSeen int64
+	// LastSeen is the last time the address was announced or connected
+	LastSeen int64
+	// Uptime is the total seconds the peer has been connected
+	Uptime int64
+	// PingTime is the last measured ping time in milliseconds, zero if
+	// not measured
+	PingTime int64
+	// Misbehavior is the number of times the host of the address banned
+	Misbehavior int64
+}
+
+// Score rates the address to connect, the higher the better. Uptime is
+// rewarded one point per hour up to 24 points, and the score decays one
+// point per day since the address was last seen. Ping time costs one point
+// per 100 milliseconds up to 10 points, and each ban costs 10 points.
+func (a *KnownAddr) Score(now time.Time) float64 {
+	score := math.Min(float64(a.Uptime)/3600, 24)
+	if elapsed := now.Unix() - a.LastSeen; elapsed > 0 {
+		score -= float64(elapsed) / 86400
+	}
+	score -= math.Min(float64(a.PingTime)/100, 10)
+	score -= float64(a.Misbehavior) * 10
+	return score
+}
+
+// Serialize the address info, the address string is not included because
+// it is used as the key in database.
+func (a *KnownAddr) Serialize(buf io.Writer) error {
+	for _, field := range []interface{}{
+		a.FirstSeen, a.LastSeen, a.Uptime, a.PingTime, a.Misbehavior,
+	} {
+		if err := binary.Write(buf, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *KnownAddr) Deserialize(data []byte) error {
+	reader := bytes.NewReader(data)
+	for _, field := range []interface{}{
+		&a.FirstSeen, &a.LastSeen, &a.Uptime,
+	} {
+		if err := binary.Read(reader, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	// Addresses stored before ping time and misbehavior were tracked
+	if reader.Len() == 0 {
+		return nil
+	}
+	for _, field := range []interface{}{
+		&a.PingTime, &a.Misbehavior,
+	} {
+		if err := binary.Read(reader, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
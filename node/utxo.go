@@ -0,0 +1,58 @@
+package node
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+type UTXO struct {
+	Op     core.OutPoint
+	Height uint32
+	core.Output
+}
+
+func NewUTXO(op *core.OutPoint, height uint32, output *core.Output) *UTXO {
+	return &UTXO{
+		Op:     *op,
+		Height: height,
+		Output: *output,
+	}
+}
+
+// Spend records the input which consumed an outpoint.
+type Spend struct {
+	TxID   common.Uint256
+	Index  uint16
+	Height uint32
+}
+
+func NewSpend(txId common.Uint256, index uint16, height uint32) *Spend {
+	return &Spend{
+		TxID:   txId,
+		Index:  index,
+		Height: height,
+	}
+}
+
+func (s *Spend) Serialize(buf io.Writer) error {
+	if err := s.TxID.Serialize(buf); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.LittleEndian, s.Index); err != nil {
+		return err
+	}
+	return binary.Write(buf, binary.LittleEndian, s.Height)
+}
+
+func (s *Spend) Deserialize(reader io.Reader) error {
+	if err := s.TxID.Deserialize(reader); err != nil {
+		return err
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &s.Index); err != nil {
+		return err
+	}
+	return binary.Read(reader, binary.LittleEndian, &s.Height)
+}
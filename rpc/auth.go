@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+)
+
+// ErrUnauthorized is returned by Authorizer if the credential is missing or
+// invalid.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// auth is nil when no credentials configured, then all requests will
+// be accepted.
+var auth *authenticator
+
+type credential struct {
+	name     string
+	password string
+	methods  map[string]struct{}
+}
+
+// allowed returns if the method can be called with the credential, a nil
+// credential means the authentication is disabled.
+func (c *credential) allowed(method string) bool {
+	if c == nil || c.methods == nil {
+		return true
+	}
+	_, ok := c.methods[method]
+	return ok
+}
+
+type authenticator struct {
+	users  map[string]*credential
+	tokens map[[sha256.Size]byte]*credential
+}
+
+func newAuthenticator(users []config.RPCUser) *authenticator {
+	if len(users) == 0 {
+		return nil
+	}
+
+	a := &authenticator{
+		users:  make(map[string]*credential),
+		tokens: make(map[[sha256.Size]byte]*credential),
+	}
+	for _, user := range users {
+		c := &credential{name: user.Name, password: user.Password}
+		if len(user.Methods) > 0 {
+			c.methods = make(map[string]struct{})
+			for _, method := range user.Methods {
+				c.methods[strings.ToLower(method)] = struct{}{}
+			}
+		}
+		if len(user.Password) > 0 {
+			a.users[user.Name] = c
+		}
+		if len(user.Token) > 0 {
+			a.tokens[sha256.Sum256([]byte(user.Token))] = c
+		}
+	}
+	return a
+}
+
+// authenticate returns the credential matches the request, nil if the
+// request has no valid basic auth or bearer token.
+func (a *authenticator) authenticate(r *http.Request) *credential {
+	if user, password, ok := r.BasicAuth(); ok {
+		c, ok := a.users[user]
+		if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(c.password)) != 1 {
+			return nil
+		}
+		return c
+	}
+
+	const bearer = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) > len(bearer) && strings.EqualFold(header[:len(bearer)], bearer) {
+		token := strings.TrimSpace(header[len(bearer):])
+		return a.tokens[sha256.Sum256([]byte(token))]
+	}
+	return nil
+}
+
+// checkAuth authenticates the request, a 401 Unauthorized error will be
+// written if failed.
+func checkAuth(w http.ResponseWriter, r *http.Request) (*credential, bool) {
+	if auth == nil {
+		return nil, true
+	}
+
+	c := auth.authenticate(r)
+	if c == nil {
+		log.Warn("[RPC] unauthorized request from ", r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", `Basic realm="SPV node"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	return c, true
+}
+
+// authorize returns a method lookup which denies the methods not allowed
+// for the credential, the denied calls are logged for audit.
+func authorize(c *credential, remote string, lookup func(string) (Method, bool)) func(string) (Method, bool) {
+	if c == nil || c.methods == nil {
+		return lookup
+	}
+	return func(name string) (Method, bool) {
+		method, ok := lookup(name)
+		if !ok || c.allowed(name) {
+			return method, ok
+		}
+		log.Warn("[RPC] denied method ", name, " for ", c.name, " from ", remote)
+		return func(Params) (Result, error) {
+			return nil, &Error{Code: Forbidden, Message: "method " + name + " not allowed"}
+		}, true
+	}
+}
+
+// Authorizer checks the requests of other servers sharing the RPC users,
+// like the gRPC server, a nil Authorizer accepts all requests.
+type Authorizer struct {
+	auth *authenticator
+}
+
+func NewAuthorizer(users []config.RPCUser) *Authorizer {
+	a := newAuthenticator(users)
+	if a == nil {
+		return nil
+	}
+	return &Authorizer{auth: a}
+}
+
+// Authorize authenticates the Authorization header value, which is basic
+// auth or bearer token, and checks the method is in the allowlist of the
+// credential. ErrUnauthorized is returned if not authenticated, and an
+// error with Forbidden code if the method not allowed.
+func (a *Authorizer) Authorize(authorization, remote, method string) error {
+	if a == nil {
+		return nil
+	}
+
+	r := &http.Request{Header: http.Header{"Authorization": []string{authorization}}}
+	c := a.auth.authenticate(r)
+	if c == nil {
+		log.Warn("[RPC] unauthorized request from ", remote)
+		return ErrUnauthorized
+	}
+
+	accept := func(string) (Method, bool) {
+		return func(Params) (Result, error) { return nil, nil }, true
+	}
+	call, _ := authorize(c, remote, accept)(strings.ToLower(method))
+	_, err := call(nil)
+	return err
+}
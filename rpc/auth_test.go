@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+)
+
+func TestAuthenticate(t *testing.T) {
+	a := newAuthenticator([]config.RPCUser{
+		{Name: "admin", Password: "secret"},
+		{Name: "reader", Token: "token", Methods: []string{"Echo"}},
+	})
+
+	request := func(set func(r *http.Request)) *http.Request {
+		r, err := http.NewRequest("POST", "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set(r)
+		return r
+	}
+	cases := []struct {
+		name   string
+		r      *http.Request
+		expect string
+	}{
+		{"basic auth", request(func(r *http.Request) { r.SetBasicAuth("admin", "secret") }), "admin"},
+		{"wrong password", request(func(r *http.Request) { r.SetBasicAuth("admin", "wrong") }), ""},
+		{"token user by password", request(func(r *http.Request) { r.SetBasicAuth("reader", "") }), ""},
+		{"bearer token", request(func(r *http.Request) { r.Header.Set("Authorization", "bearer token") }), "reader"},
+		{"wrong token", request(func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") }), ""},
+		{"no credential", request(func(r *http.Request) {}), ""},
+	}
+	for _, c := range cases {
+		var name string
+		if credential := a.authenticate(c.r); credential != nil {
+			name = credential.name
+		}
+		if name != c.expect {
+			t.Fatalf("%s authenticated as %q, expect %q", c.name, name, c.expect)
+		}
+	}
+
+	if newAuthenticator(nil) != nil {
+		t.Fatal("authentication enabled without users")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	a := newAuthenticator([]config.RPCUser{
+		{Name: "admin", Password: "secret"},
+		{Name: "reader", Password: "secret", Methods: []string{"Echo"}},
+	})
+
+	cases := []struct {
+		credential *credential
+		body       string
+		response   string
+		status     int
+	}{
+		{a.users["reader"], `{"id":1,"method":"echo","params":{"value":"a"}}`,
+			`{"id":1,"result":"a"}`, http.StatusOK},
+		{a.users["reader"], `{"id":1,"method":"unknown"}`,
+			`{"id":1,"error":{"code":-32601,"message":"method unknown not found"}}`, http.StatusNotFound},
+		{a.users["admin"], `{"id":1,"method":"echo","params":{"value":"a"}}`,
+			`{"id":1,"result":"a"}`, http.StatusOK},
+		{nil, `{"id":1,"method":"echo","params":{"value":"a"}}`,
+			`{"id":1,"result":"a"}`, http.StatusOK},
+	}
+	for _, c := range cases {
+		response, status := handleBody([]byte(c.body), authorize(c.credential, "test", testLookup))
+		data := marshalTestResponse(t, response)
+		if data != c.response || status != c.status {
+			t.Fatalf("request %s got %s %d, expect %s %d",
+				c.body, data, status, c.response, c.status)
+		}
+	}
+
+	// Methods not in the allowlist are denied
+	restricted := &credential{name: "reader", methods: map[string]struct{}{"other": {}}}
+	response, status := handleBody([]byte(`{"id":1,"method":"echo","params":{"value":"a"}}`),
+		authorize(restricted, "test", testLookup))
+	expect := `{"id":1,"error":{"code":-32001,"message":"method echo not allowed"}}`
+	if data := marshalTestResponse(t, response); data != expect || status != http.StatusForbidden {
+		t.Fatalf("denied method got %s %d, expect %s %d", data, status, expect, http.StatusForbidden)
+	}
+}
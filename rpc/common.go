@@ -1,18 +1,32 @@
 package rpc
 
 import (
+	"github.com/elastos/Elastos.ELA.Utility/common"
 	. "github.com/elastos/Elastos.ELA/core"
 )
 
 type AttributeInfo struct {
 	Usage AttributeUsage `json:"usage"`
 	Data  string         `json:"data"`
+	// Value is the data decoded by usage, omitted if it can not be decoded
+	Value interface{} `json:"value,omitempty"`
 }
 
 type InputInfo struct {
 	TxID     string `json:"txid"`
 	VOut     uint16 `json:"vout"`
 	Sequence uint32 `json:"sequence"`
+	// Previous output of the input, omitted if it's unknown to SPV node
+	Address string `json:"address,omitempty"`
+	Value   string `json:"value,omitempty"`
+	AssetID string `json:"assetid,omitempty"`
+	// PrevOut is "found" or "unknown" in prevout lookup mode
+	PrevOut string `json:"prevout,omitempty"`
+}
+
+type FeeInfo struct {
+	AssetID string `json:"assetid"`
+	Fee     string `json:"fee"`
 }
 
 type OutputInfo struct {
@@ -28,6 +42,49 @@ type ProgramInfo struct {
 	Parameter string `json:"parameter"`
 }
 
+type PayloadInfo interface{}
+
+type CoinbaseInfo struct {
+	CoinbaseData string
+}
+
+type RegisterAssetInfo struct {
+	Asset      Asset
+	Amount     string
+	Controller string
+}
+
+type TransferAssetInfo struct{}
+
+type RecordInfo struct {
+	RecordType string
+	RecordData string
+}
+
+type SideChainPowInfo struct {
+	BlockHeight     uint32
+	SideBlockHash   string
+	SideGenesisHash string
+	SignedData      string
+}
+
+type RechargeToSideChainInfo struct {
+	Proof                string
+	MainChainTransaction string
+}
+
+type WithdrawFromSideChainInfo struct {
+	BlockHeight                uint32
+	GenesisBlockAddress        string
+	SideChainTransactionHashes []string
+}
+
+type TransferCrossChainAssetInfo struct {
+	CrossChainAddresses []string
+	OutputIndexes       []uint64
+	CrossChainAmounts   []common.Fixed64
+}
+
 type TransactionInfo struct {
 	TxId           string          `json:"txid,omitempty"`
 	Hash           string          `json:"hash,omitempty"`
@@ -43,29 +100,143 @@ type TransactionInfo struct {
 	BlockTime      uint32          `json:"blocktime,omitempty"`
 	TxType         TransactionType `json:"type"`
 	PayloadVersion byte            `json:"payloadversion,omitempty"`
-	Payload        interface{}     `json:"payload,omitempty"`
+	Payload        PayloadInfo     `json:"payload,omitempty"`
 	Attributes     []AttributeInfo `json:"attributes"`
 	Programs       []ProgramInfo   `json:"programs,omitempty"`
+	// Fees by asset, omitted if any previous output of the inputs unknown
+	Fees []FeeInfo `json:"fees,omitempty"`
 }
 
+type HeaderInfo struct {
+	Hash              string `json:"hash"`
+	Confirmations     uint32 `json:"confirmations"`
+	Height            uint32 `json:"height"`
+	Version           uint32 `json:"version"`
+	VersionHex        string `json:"versionhex"`
+	MerkleRoot        string `json:"merkleroot"`
+	Time              uint32 `json:"time"`
+	MedianTime        uint32 `json:"mediantime"`
+	Nonce             uint32 `json:"nonce"`
+	Bits              uint32 `json:"bits"`
+	Difficulty        string `json:"difficulty"`
+	ChainWork         string `json:"chainwork"`
+	PreviousBlockHash string `json:"previousblockhash"`
+	NextBlockHash     string `json:"nextblockhash,omitempty"`
+	AuxPow            string `json:"auxpow"`
+}
+
+// BlockInfo is the header info with the transactions of the block.
 type BlockInfo struct {
-	Hash              string        `json:"hash"`
-	Confirmations     uint32        `json:"confirmations"`
-	StrippedSize      uint32        `json:"strippedsize"`
-	Size              uint32        `json:"size"`
-	Weight            uint32        `json:"weight"`
-	Height            uint32        `json:"height"`
-	Version           uint32        `json:"version"`
-	VersionHex        string        `json:"versionhex"`
-	MerkleRoot        string        `json:"merkleroot"`
-	Tx                []interface{} `json:"tx"`
-	Time              uint32        `json:"time"`
-	MedianTime        uint32        `json:"mediantime"`
-	Nonce             uint32        `json:"nonce"`
-	Bits              uint32        `json:"bits"`
-	Difficulty        string        `json:"difficulty"`
-	ChainWork         string        `json:"chainwork"`
-	PreviousBlockHash string        `json:"previousblockhash"`
-	NextBlockHash     string        `json:"nextblockhash,omitempty"`
-	AuxPow            string        `json:"auxpow"`
+	*HeaderInfo
+	StrippedSize uint32        `json:"strippedsize"`
+	Size         uint32        `json:"size"`
+	Weight       uint32        `json:"weight"`
+	Tx           []interface{} `json:"tx"`
+}
+
+type BlockchainInfo struct {
+	Magic                uint32  `json:"magic"`
+	Blocks               uint32  `json:"blocks"`
+	Headers              uint32  `json:"headers"`
+	BestBlockHash        string  `json:"bestblockhash"`
+	Difficulty           string  `json:"difficulty"`
+	MedianTime           uint32  `json:"mediantime"`
+	ChainWork            string  `json:"chainwork"`
+	State                string  `json:"state"`
+	EstimatedHeight      uint32  `json:"estimatedheight"`
+	VerificationProgress float64 `json:"verificationprogress"`
+	Rescanning           bool    `json:"rescanning"`
+}
+
+type ChainTipInfo struct {
+	Height    uint32 `json:"height"`
+	Hash      string `json:"hash"`
+	BranchLen uint32 `json:"branchlen"`
+	Status    string `json:"status"`
+}
+
+type PeerInfo struct {
+	ID           uint64  `json:"id"`
+	Addr         string  `json:"addr"`
+	Version      uint32  `json:"version"`
+	Services     uint64  `json:"services"`
+	Height       uint64  `json:"height"`
+	LastActive   int64   `json:"lastactive"`
+	PingTime     float64 `json:"pingtime,omitempty"`
+	FilterLoaded int64   `json:"filterloaded,omitempty"`
+}
+
+type NetworkInfo struct {
+	Magic            uint32   `json:"magic"`
+	Connections      int      `json:"connections"`
+	MaxConnections   int      `json:"maxconnections"`
+	KnownAddrs       int      `json:"knownaddresses"`
+	Seeds            []string `json:"seeds"`
+	State            string   `json:"state"`
+	LastFilterReload int64    `json:"lastfilterreload"`
+}
+
+type BanInfo struct {
+	Address     string `json:"address"`
+	BanCreated  int64  `json:"bancreated"`
+	BannedUntil int64  `json:"banneduntil"`
+	Reason      string `json:"reason"`
+}
+
+type UTXOInfo struct {
+	TxID          string `json:"txid"`
+	VOut          uint16 `json:"vout"`
+	Address       string `json:"address"`
+	Amount        string `json:"amount"`
+	AssetID       string `json:"assetid"`
+	OutputLock    uint32 `json:"outputlock"`
+	Confirmations uint32 `json:"confirmations"`
+}
+
+type AddrTxInfo struct {
+	TxID          string `json:"txid"`
+	Direction     string `json:"direction"`
+	Amount        string `json:"amount"`
+	Height        uint32 `json:"height"`
+	Confirmations uint32 `json:"confirmations"`
+}
+
+type AddrHistoryInfo struct {
+	Address      string       `json:"address"`
+	Transactions []AddrTxInfo `json:"transactions"`
+	Cursor       string       `json:"cursor,omitempty"`
+}
+
+type AddressInfo struct {
+	Address        string `json:"address"`
+	Label          string `json:"label,omitempty"`
+	RegisterTime   int64  `json:"registertime,omitempty"`
+	RegisterHeight uint32 `json:"registerheight,omitempty"`
+	Birthday       uint32 `json:"birthday,omitempty"`
+	TxCount        int    `json:"txcount"`
+	Balance        string `json:"balance"`
+}
+
+type RescanInfo struct {
+	InProgress    bool     `json:"inprogress"`
+	StartHeight   uint32   `json:"startheight"`
+	CurrentHeight uint32   `json:"currentheight"`
+	TargetHeight  uint32   `json:"targetheight"`
+	Progress      float64  `json:"progress"`
+	Addresses     []string `json:"addresses,omitempty"`
+}
+
+type ConfirmationInfo struct {
+	TxID          string `json:"txid"`
+	Height        uint32 `json:"height"`
+	Confirmations uint32 `json:"confirmations"`
+}
+
+type RollbackInfo struct {
+	Height       uint32   `json:"height"`
+	Transactions []string `json:"transactions"`
+}
+
+type SyncStateInfo struct {
+	State string `json:"state"`
 }
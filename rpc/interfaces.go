@@ -4,9 +4,15 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
+	"sort"
 	"strconv"
+	"time"
+	"unicode/utf8"
 
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
 	"github.com/elastos/Elastos.ELA.SPV.Node/node"
 
 	"github.com/elastos/Elastos.ELA.Utility/common"
@@ -17,7 +23,7 @@ import (
 func RegisterAddresses(params Params) (Result, error) {
 	addresses, ok := params["addresses"].([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("[RegisterAddresses] parameter addresses not exist")
+		return nil, invalidParams("[RegisterAddresses] parameter addresses not exist")
 	}
 	addrs := make([]string, 0, len(addresses))
 	for _, address := range addresses {
@@ -25,11 +31,13 @@ func RegisterAddresses(params Params) (Result, error) {
 		case string:
 			addrs = append(addrs, addr)
 		default:
-			return nil, fmt.Errorf("[RegisterAddresses] address not in string format")
+			return nil, invalidParams("[RegisterAddresses] address not in string format")
 		}
 	}
 
-	err := Node.RegisterAddresses(addrs)
+	birthday, _ := params.Uint("birthday")
+
+	err := Node.RegisterAddresses(addrs, birthday)
 	if err != nil {
 		return nil, fmt.Errorf("[RegisterAddresses] register addresses failed %s", err.Error())
 	}
@@ -39,16 +47,105 @@ func RegisterAddresses(params Params) (Result, error) {
 func RegisterAddress(params Params) (Result, error) {
 	address, ok := params.String("address")
 	if !ok {
-		return nil, fmt.Errorf("[RegisterAddress] parameter address not exist")
+		return nil, invalidParams("[RegisterAddress] parameter address not exist")
 	}
+	label, _ := params.String("label")
+	birthday, _ := params.Uint("birthday")
 
-	err := Node.RegisterAddress(address)
+	err := Node.RegisterAddress(address, label, birthday)
 	if err != nil {
 		return nil, fmt.Errorf("[RegisterAddress] register address %s error %s", address, err.Error())
 	}
 	return nil, err
 }
 
+func UnregisterAddresses(params Params) (Result, error) {
+	addresses, ok := params.Strings("addresses")
+	if !ok {
+		return nil, invalidParams("[UnregisterAddresses] parameter addresses not exist or not in string format")
+	}
+	purge, _ := params.Bool("purge")
+
+	err := Node.UnregisterAddresses(addresses, purge)
+	if err != nil {
+		return nil, fmt.Errorf("[UnregisterAddresses] unregister addresses failed %s", err.Error())
+	}
+	return nil, nil
+}
+
+func UnregisterAddress(params Params) (Result, error) {
+	address, ok := params.String("address")
+	if !ok {
+		return nil, invalidParams("[UnregisterAddress] parameter address not exist")
+	}
+	purge, _ := params.Bool("purge")
+
+	err := Node.UnregisterAddress(address, purge)
+	if err != nil {
+		return nil, fmt.Errorf("[UnregisterAddress] unregister address %s error %s", address, err.Error())
+	}
+	return nil, nil
+}
+
+func GetAddresses(params Params) (Result, error) {
+	addrs, err := Node.GetStoreAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("[GetAddresses] query addresses failed %s", err.Error())
+	}
+
+	utxos, err := Node.GetUTXOs()
+	if err != nil {
+		return nil, fmt.Errorf("[GetAddresses] query unspent outputs failed %s", err.Error())
+	}
+	balances := make(map[common.Uint168]common.Fixed64)
+	for _, utxo := range utxos {
+		if utxo.AssetID == node.AssetEla && getConfirmations(utxo.Height) > 0 {
+			balances[utxo.ProgramHash] += utxo.Value
+		}
+	}
+
+	addresses := make([]AddressInfo, 0, len(addrs))
+	for _, addr := range addrs {
+		txCount, err := Node.GetAddrTxCount(&addr.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("[GetAddresses] query transactions of %s failed %s",
+				addr.Address, err.Error())
+		}
+		addresses = append(addresses, AddressInfo{
+			Address:        addr.Address,
+			Label:          addr.Label,
+			RegisterTime:   addr.Time,
+			RegisterHeight: addr.Height,
+			Birthday:       addr.Birthday,
+			TxCount:        txCount,
+			Balance:        balances[addr.Hash].String(),
+		})
+	}
+	return addresses, nil
+}
+
+func Rescan(params Params) (Result, error) {
+	// Start from the earliest birthday of addresses if not specified
+	start, _ := params.Uint("startheight")
+
+	var addresses []string
+	if _, ok := params["addresses"]; ok {
+		addresses, ok = params.Strings("addresses")
+		if !ok {
+			return nil, invalidParams("[Rescan] addresses not in string array format")
+		}
+	}
+
+	if err := Node.Rescan(start, addresses); err != nil {
+		return nil, fmt.Errorf("[Rescan] start rescan failed %s", err.Error())
+	}
+	return getRescanInfo(Node.RescanInfo()), nil
+}
+
+func GetRescanInfo(params Params) (Result, error) {
+	return getRescanInfo(Node.RescanInfo()), nil
+}
+
 func GetBlockCount(params Params) (Result, error) {
 	tip, err := Node.GetBestHeader()
 	if err != nil {
@@ -57,6 +154,206 @@ func GetBlockCount(params Params) (Result, error) {
 	return tip.Height, nil
 }
 
+func GetBlockchainInfo(params Params) (Result, error) {
+	tip, err := Node.GetBestHeader()
+	if err != nil {
+		return nil, fmt.Errorf("[GetBlockchainInfo] query best header failed %s", err.Error())
+	}
+	info, err := getHeaderInfo(&tip.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	estimated := Node.EstimatedHeight()
+	var progress float64 = 1
+	if estimated > tip.Height {
+		progress = float64(tip.Height) / float64(estimated)
+	}
+
+	return &BlockchainInfo{
+		Magic:                config.Values().Magic,
+		Blocks:               tip.Height,
+		Headers:              tip.Height,
+		BestBlockHash:        info.Hash,
+		Difficulty:           info.Difficulty,
+		MedianTime:           info.MedianTime,
+		ChainWork:            info.ChainWork,
+		State:                getSyncState(Node.ChainState()),
+		EstimatedHeight:      estimated,
+		VerificationProgress: progress,
+		Rescanning:           Node.RescanInfo().InProgress,
+	}, nil
+}
+
+func GetChainTips(params Params) (Result, error) {
+	tips, err := Node.GetChainTips()
+	if err != nil {
+		return nil, fmt.Errorf("[GetChainTips] query chain tips failed %s", err.Error())
+	}
+
+	infos := make([]ChainTipInfo, 0, len(tips))
+	for _, tip := range tips {
+		status := "valid-headers"
+		if tip.BranchLen == 0 {
+			status = "active"
+		}
+		infos = append(infos, ChainTipInfo{
+			Height:    tip.Height,
+			Hash:      tip.Hash.String(),
+			BranchLen: tip.BranchLen,
+			Status:    status,
+		})
+	}
+	return infos, nil
+}
+
+func GetPeerInfo(params Params) (Result, error) {
+	peers := Node.GetPeers()
+	infos := make([]PeerInfo, 0, len(peers))
+	for _, peer := range peers {
+		info := PeerInfo{
+			ID:         peer.ID,
+			Addr:       peer.Addr,
+			Version:    peer.Version,
+			Services:   peer.Services,
+			Height:     peer.Height,
+			LastActive: peer.LastActive.Unix(),
+			PingTime:   peer.PingTime.Seconds(),
+		}
+		if !peer.FilterLoaded.IsZero() {
+			info.FilterLoaded = peer.FilterLoaded.Unix()
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func GetConnectionCount(params Params) (Result, error) {
+	return Node.ConnectionCount(), nil
+}
+
+func GetNetworkInfo(params Params) (Result, error) {
+	seeds := config.Values().SeedList
+	if seeds == nil {
+		seeds = []string{}
+	}
+	knownAddrs, err := Node.KnownAddrCount()
+	if err != nil {
+		return nil, fmt.Errorf("[GetNetworkInfo] query address book failed %s", err.Error())
+	}
+	var filterReload int64
+	if reloaded := Node.LastFilterReload(); !reloaded.IsZero() {
+		filterReload = reloaded.Unix()
+	}
+	return &NetworkInfo{
+		Magic:            config.Values().Magic,
+		Connections:      Node.ConnectionCount(),
+		MaxConnections:   node.MaxConnections(),
+		KnownAddrs:       knownAddrs,
+		Seeds:            seeds,
+		State:            getSyncState(Node.ChainState()),
+		LastFilterReload: filterReload,
+	}, nil
+}
+
+func AddNode(params Params) (Result, error) {
+	addr, ok := params.String("node")
+	if !ok {
+		return nil, invalidParams("[AddNode] parameter node not exist")
+	}
+	command, ok := params.String("command")
+	if !ok {
+		return nil, invalidParams("[AddNode] parameter command not exist")
+	}
+	switch command {
+	case node.AddNodeAdd, node.AddNodeRemove, node.AddNodeOneTry:
+	default:
+		return nil, invalidParams("[AddNode] unknown command %s, must be add, remove or onetry", command)
+	}
+
+	if err := Node.AddNode(addr, command); err != nil {
+		return nil, fmt.Errorf("[AddNode] %s node %s failed %s", command, addr, err.Error())
+	}
+	return nil, nil
+}
+
+func DisconnectNode(params Params) (Result, error) {
+	addr, ok := params.String("address")
+	if !ok {
+		return nil, invalidParams("[DisconnectNode] parameter address not exist")
+	}
+
+	if err := Node.DisconnectNode(addr); err != nil {
+		return nil, fmt.Errorf("[DisconnectNode] disconnect node failed %s", err.Error())
+	}
+	return nil, nil
+}
+
+func SetBan(params Params) (Result, error) {
+	addr, ok := params.String("address")
+	if !ok {
+		return nil, invalidParams("[SetBan] parameter address not exist")
+	}
+	command, ok := params.String("command")
+	if !ok {
+		return nil, invalidParams("[SetBan] parameter command not exist")
+	}
+
+	switch command {
+	case "add":
+		until := time.Now().Add(node.DefaultBanDuration)
+		if _, ok := params["bantime"]; ok {
+			banTime, ok := params.Int("bantime")
+			if !ok || banTime <= 0 {
+				return nil, invalidParams("[SetBan] invalid bantime")
+			}
+			if absolute, _ := params.Bool("absolute"); absolute {
+				until = time.Unix(banTime, 0)
+			} else {
+				until = time.Now().Add(time.Duration(banTime) * time.Second)
+			}
+		}
+		if err := Node.Ban(addr, until, "manually banned"); err != nil {
+			return nil, fmt.Errorf("[SetBan] ban %s failed %s", addr, err.Error())
+		}
+	case "remove":
+		if err := Node.Unban(addr); err != nil {
+			return nil, fmt.Errorf("[SetBan] unban %s failed %s", addr, err.Error())
+		}
+	default:
+		return nil, invalidParams("[SetBan] unknown command %s, must be add or remove", command)
+	}
+	return nil, nil
+}
+
+func ListBanned(params Params) (Result, error) {
+	bans, err := Node.GetBans()
+	if err != nil {
+		return nil, fmt.Errorf("[ListBanned] query banned hosts failed %s", err.Error())
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Created < bans[j].Created
+	})
+
+	infos := make([]BanInfo, 0, len(bans))
+	for _, ban := range bans {
+		infos = append(infos, BanInfo{
+			Address:     ban.Host,
+			BanCreated:  ban.Created,
+			BannedUntil: ban.Until,
+			Reason:      ban.Reason,
+		})
+	}
+	return infos, nil
+}
+
+func ClearBanned(params Params) (Result, error) {
+	if err := Node.ClearBans(); err != nil {
+		return nil, fmt.Errorf("[ClearBanned] clear banned hosts failed %s", err.Error())
+	}
+	return nil, nil
+}
+
 func GetBestBlockHash(params Params) (Result, error) {
 	tip, err := Node.GetBestHeader()
 	if err != nil {
@@ -68,7 +365,7 @@ func GetBestBlockHash(params Params) (Result, error) {
 func GetBlockHash(params Params) (Result, error) {
 	height, ok := params.Uint("index")
 	if !ok {
-		return nil, fmt.Errorf("[GetBlockHash] parameter index not exist")
+		return nil, invalidParams("[GetBlockHash] parameter index not exist")
 	}
 	hash, err := Node.GetHeaderHash(height)
 	if err != nil {
@@ -80,15 +377,15 @@ func GetBlockHash(params Params) (Result, error) {
 func GetBlock(params Params) (Result, error) {
 	hex, ok := params.String("hash")
 	if !ok {
-		return nil, fmt.Errorf("[GetBlock] parameter hash not exist")
+		return nil, invalidParams("[GetBlock] parameter hash not exist")
 	}
 	data, err := common.HexStringToBytes(hex)
 	if err != nil {
-		return nil, fmt.Errorf("[GetBlock] convert hex string failed %s", err.Error())
+		return nil, invalidParams("[GetBlock] convert hex string failed %s", err.Error())
 	}
 	hash, err := common.Uint256FromBytes(data)
 	if err != nil {
-		return nil, fmt.Errorf("[GetBlock] parse Uint256 failed %s", err.Error())
+		return nil, invalidParams("[GetBlock] parse Uint256 failed %s", err.Error())
 	}
 
 	format, ok := params.Uint("format")
@@ -102,7 +399,7 @@ func GetBlock(params Params) (Result, error) {
 func GetBlockByHeight(params Params) (Result, error) {
 	height, ok := params.Uint("height")
 	if !ok {
-		return nil, fmt.Errorf("[GetBlockByHeight] parameter height not exist")
+		return nil, invalidParams("[GetBlockByHeight] parameter height not exist")
 	}
 	hash, err := Node.GetHeaderHash(height)
 	if err != nil {
@@ -117,20 +414,55 @@ func GetBlockByHeight(params Params) (Result, error) {
 	return getBlock(hash, format)
 }
 
+func GetBlockHeader(params Params) (Result, error) {
+	var hash *common.Uint256
+	if hex, ok := params.String("hash"); ok {
+		var err error
+		hash, err = uint256FromHex(hex)
+		if err != nil {
+			return nil, invalidParams("[GetBlockHeader] parse hash failed %s", err.Error())
+		}
+	} else if height, ok := params.Uint("height"); ok {
+		var err error
+		hash, err = Node.GetHeaderHash(height)
+		if err != nil {
+			return nil, fmt.Errorf("[GetBlockHeader] query header at height %d failed %s", height, err.Error())
+		}
+	} else {
+		return nil, invalidParams("[GetBlockHeader] parameter hash or height not exist")
+	}
+
+	storeHeader, err := Node.GetHeader(hash)
+	if err != nil {
+		return nil, fmt.Errorf("[GetBlockHeader] unknown block with hash %s", hash.String())
+	}
+
+	verbose, ok := params.Bool("verbose")
+	if !ok || verbose {
+		return getHeaderInfo(&storeHeader.Header)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := storeHeader.Header.Serialize(buf); err != nil {
+		return nil, err
+	}
+	return common.BytesToHexString(buf.Bytes()), nil
+}
+
 func GetRawTransaction(params Params) (Result, error) {
 	hex, ok := params.String("hash")
 	if !ok {
-		return nil, fmt.Errorf("[GetRawTransaction] parameter hash not exist")
+		return nil, invalidParams("[GetRawTransaction] parameter hash not exist")
 	}
 
 	data, err := common.HexStringToBytes(hex)
 	if err != nil {
-		return nil, fmt.Errorf("[GetRawTransaction] convert hash hex string failed %s", err.Error())
+		return nil, invalidParams("[GetRawTransaction] convert hash hex string failed %s", err.Error())
 	}
 
 	hash, err := common.Uint256FromBytes(data)
 	if err != nil {
-		return nil, fmt.Errorf("[GetRawTransaction] parse hash bytes failed %s", err.Error())
+		return nil, invalidParams("[GetRawTransaction] parse hash bytes failed %s", err.Error())
 	}
 	tx, err := Node.GetTx(hash)
 	if err != nil {
@@ -164,15 +496,15 @@ func GetRawTransaction(params Params) (Result, error) {
 			}
 			return common.BytesToHexString(buf.Bytes()), nil
 		case "json":
-			return getTransactionInfo(&header.Header, &tx.Transaction), nil
+			return getVerboseTransaction(&header.Header, &tx.Transaction, params), nil
 		default:
-			return nil, fmt.Errorf("[GetRawTransaction] unspported format %s", format)
+			return nil, invalidParams("[GetRawTransaction] unspported format %s", format)
 		}
 	}
 
 	decoded, ok := params.Bool("format")
 	if ok && decoded {
-		return getTransactionInfo(&header.Header, &tx.Transaction), nil
+		return getVerboseTransaction(&header.Header, &tx.Transaction, params), nil
 	}
 
 	buf := new(bytes.Buffer)
@@ -185,11 +517,11 @@ func GetRawTransaction(params Params) (Result, error) {
 func SendRawTransaction(params Params) (Result, error) {
 	data, ok := params.String("data")
 	if !ok {
-		return nil, fmt.Errorf("[SendRawTransaction] parameter data not exist")
+		return nil, invalidParams("[SendRawTransaction] parameter data not exist")
 	}
 	txBytes, err := common.HexStringToBytes(data)
 	if err != nil {
-		return nil, fmt.Errorf("[SendRawTransaction] parse data hex string failed %s", err.Error())
+		return nil, invalidParams("[SendRawTransaction] parse data hex string failed %s", err.Error())
 	}
 
 	format, ok := params.String("format")
@@ -201,11 +533,11 @@ func SendRawTransaction(params Params) (Result, error) {
 		var btcTx auxpow.BtcTx
 		err = btcTx.Deserialize(bytes.NewReader(txBytes))
 		if err != nil {
-			return nil, fmt.Errorf("[SendRawTransaction] transaction deserialize failed %s", err.Error())
+			return nil, invalidParams("[SendRawTransaction] transaction deserialize failed %s", err.Error())
 		}
 		tx, err := btcTxToElaTx(&btcTx)
 		if err != nil {
-			return nil, fmt.Errorf(
+			return nil, invalidParams(
 				"[SendRawTransaction] convert btc transaction to ela transaction failed %s", err.Error())
 		}
 		txId, err := Node.SendTransaction(*tx)
@@ -214,12 +546,315 @@ func SendRawTransaction(params Params) (Result, error) {
 		var tx core.Transaction
 		err = tx.Deserialize(bytes.NewReader(txBytes))
 		if err != nil {
-			return nil, fmt.Errorf("[SendRawTransaction] transaction deserialize failed %s", err.Error())
+			return nil, invalidParams("[SendRawTransaction] transaction deserialize failed %s", err.Error())
 		}
 		txId, err := Node.SendTransaction(tx)
 		return txId.String(), err
 	}
-	return nil, fmt.Errorf("[SendRawTransaction] unknown transaction format %s", format)
+	return nil, invalidParams("[SendRawTransaction] unknown transaction format %s", format)
+}
+
+func GetTxOutProof(params Params) (Result, error) {
+	hex, ok := params.String("txid")
+	if !ok {
+		return nil, invalidParams("[GetTxOutProof] parameter txid not exist")
+	}
+	txId, err := uint256FromHex(hex)
+	if err != nil {
+		return nil, invalidParams("[GetTxOutProof] parse txid failed %s", err.Error())
+	}
+
+	proof, err := Node.GetMerkleProof(txId)
+	if err != nil {
+		return nil, fmt.Errorf("[GetTxOutProof] query merkle proof failed %s", err.Error())
+	}
+	header, err := Node.GetHeader(&proof.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("[GetTxOutProof] query header %s failed %s",
+			proof.BlockHash.String(), err.Error())
+	}
+
+	// The proof is the serialized block header followed by the merkle proof
+	buf := new(bytes.Buffer)
+	if err := header.Header.Serialize(buf); err != nil {
+		return nil, err
+	}
+	if err := proof.Serialize(buf); err != nil {
+		return nil, err
+	}
+	return common.BytesToHexString(buf.Bytes()), nil
+}
+
+func VerifyTxOutProof(params Params) (Result, error) {
+	hex, ok := params.String("proof")
+	if !ok {
+		return nil, invalidParams("[VerifyTxOutProof] parameter proof not exist")
+	}
+	data, err := common.HexStringToBytes(hex)
+	if err != nil {
+		return nil, invalidParams("[VerifyTxOutProof] convert proof hex string failed %s", err.Error())
+	}
+
+	var header core.Header
+	var proof node.MerkleProof
+	reader := bytes.NewReader(data)
+	if err := header.Deserialize(reader); err != nil {
+		return nil, invalidParams("[VerifyTxOutProof] deserialize header failed %s", err.Error())
+	}
+	if err := proof.Deserialize(reader); err != nil {
+		return nil, invalidParams("[VerifyTxOutProof] deserialize merkle proof failed %s", err.Error())
+	}
+
+	hash := header.Hash()
+	if proof.BlockHash != hash {
+		return nil, fmt.Errorf("[VerifyTxOutProof] block hash not match")
+	}
+	root, err := proof.Root()
+	if err != nil {
+		return nil, fmt.Errorf("[VerifyTxOutProof] invalid merkle proof %s", err.Error())
+	}
+	if root != header.MerkleRoot {
+		return nil, fmt.Errorf("[VerifyTxOutProof] merkle root not match")
+	}
+
+	// The block must be in our best chain
+	mainHash, err := Node.GetHeaderHash(header.Height)
+	if err != nil || *mainHash != hash {
+		return []string{}, nil
+	}
+	return []string{common.BytesToHexString(proof.TxID.Bytes())}, nil
+}
+
+func ListUnspent(params Params) (Result, error) {
+	minConf, ok := params.Uint("minconf")
+	if !ok {
+		minConf = 1
+	}
+	maxConf, ok := params.Uint("maxconf")
+	if !ok {
+		maxConf = math.MaxUint32
+	}
+
+	var addrFilter map[common.Uint168]struct{}
+	if _, ok := params["addresses"]; ok {
+		addresses, ok := params.Strings("addresses")
+		if !ok {
+			return nil, invalidParams("[ListUnspent] addresses not in string array format")
+		}
+		addrFilter = make(map[common.Uint168]struct{})
+		for _, address := range addresses {
+			hash, err := common.Uint168FromAddress(address)
+			if err != nil {
+				return nil, invalidParams("[ListUnspent] invalid address %s", address)
+			}
+			addrFilter[*hash] = struct{}{}
+		}
+	}
+
+	var assetId *common.Uint256
+	if hex, ok := params.String("assetid"); ok {
+		var err error
+		assetId, err = uint256FromHex(hex)
+		if err != nil {
+			return nil, invalidParams("[ListUnspent] parse assetid failed %s", err.Error())
+		}
+	}
+
+	utxos, err := Node.GetUTXOs()
+	if err != nil {
+		return nil, fmt.Errorf("[ListUnspent] query unspent outputs failed %s", err.Error())
+	}
+
+	unspents := make([]UTXOInfo, 0, len(utxos))
+	for _, utxo := range utxos {
+		if addrFilter != nil {
+			if _, ok := addrFilter[utxo.ProgramHash]; !ok {
+				continue
+			}
+		}
+		if assetId != nil && utxo.AssetID != *assetId {
+			continue
+		}
+
+		confirmations := getConfirmations(utxo.Height)
+		if confirmations < minConf || confirmations > maxConf {
+			continue
+		}
+
+		address, _ := utxo.ProgramHash.ToAddress()
+		unspents = append(unspents, UTXOInfo{
+			TxID:          common.BytesToHexString(utxo.Op.TxID.Bytes()),
+			VOut:          utxo.Op.Index,
+			Address:       address,
+			Amount:        utxo.Value.String(),
+			AssetID:       common.BytesToHexString(utxo.AssetID.Bytes()),
+			OutputLock:    utxo.OutputLock,
+			Confirmations: confirmations,
+		})
+	}
+	return unspents, nil
+}
+
+func GetBalance(params Params) (Result, error) {
+	var programHash *common.Uint168
+	if address, ok := params.String("address"); ok && address != "*" {
+		var err error
+		programHash, err = common.Uint168FromAddress(address)
+		if err != nil {
+			return nil, invalidParams("[GetBalance] invalid address %s", address)
+		}
+	}
+
+	minConf, ok := params.Uint("minconf")
+	if !ok {
+		minConf = 1
+	}
+
+	assetId := node.AssetEla
+	if hex, ok := params.String("assetid"); ok {
+		id, err := uint256FromHex(hex)
+		if err != nil {
+			return nil, invalidParams("[GetBalance] parse assetid failed %s", err.Error())
+		}
+		assetId = *id
+	}
+
+	utxos, err := Node.GetUTXOs()
+	if err != nil {
+		return nil, fmt.Errorf("[GetBalance] query unspent outputs failed %s", err.Error())
+	}
+
+	var balance common.Fixed64
+	for _, utxo := range utxos {
+		if programHash != nil && utxo.ProgramHash != *programHash {
+			continue
+		}
+		if utxo.AssetID != assetId || getConfirmations(utxo.Height) < minConf {
+			continue
+		}
+		balance += utxo.Value
+	}
+	return balance.String(), nil
+}
+
+func GetReceivedByAddress(params Params) (Result, error) {
+	address, ok := params.String("address")
+	if !ok {
+		return nil, invalidParams("[GetReceivedByAddress] parameter address not exist")
+	}
+	programHash, err := common.Uint168FromAddress(address)
+	if err != nil {
+		return nil, invalidParams("[GetReceivedByAddress] invalid address %s", address)
+	}
+
+	minConf, ok := params.Uint("minconf")
+	if !ok {
+		minConf = 1
+	}
+
+	assetId := node.AssetEla
+	if hex, ok := params.String("assetid"); ok {
+		id, err := uint256FromHex(hex)
+		if err != nil {
+			return nil, invalidParams("[GetReceivedByAddress] parse assetid failed %s", err.Error())
+		}
+		assetId = *id
+	}
+
+	outputs, err := Node.GetOutputs()
+	if err != nil {
+		return nil, fmt.Errorf("[GetReceivedByAddress] query outputs failed %s", err.Error())
+	}
+
+	var received common.Fixed64
+	for _, output := range outputs {
+		if output.ProgramHash != *programHash || output.AssetID != assetId {
+			continue
+		}
+		if getConfirmations(output.Height) < minConf {
+			continue
+		}
+		received += output.Value
+	}
+	return received.String(), nil
+}
+
+func GetAddressHistory(params Params) (Result, error) {
+	address, ok := params.String("address")
+	if !ok {
+		return nil, invalidParams("[GetAddressHistory] parameter address not exist")
+	}
+	programHash, err := common.Uint168FromAddress(address)
+	if err != nil {
+		return nil, invalidParams("[GetAddressHistory] invalid address %s", address)
+	}
+
+	count, ok := params.Uint("count")
+	if !ok {
+		count = 10
+	}
+	skip, _ := params.Uint("skip")
+
+	var reverse = true
+	if order, ok := params.String("order"); ok {
+		switch order {
+		case "desc":
+		case "asc":
+			reverse = false
+		default:
+			return nil, invalidParams("[GetAddressHistory] unknown order %s", order)
+		}
+	}
+
+	var cursor []byte
+	if hex, ok := params.String("cursor"); ok {
+		cursor, err = common.HexStringToBytes(hex)
+		if err != nil {
+			return nil, invalidParams("[GetAddressHistory] convert cursor hex string failed %s", err.Error())
+		}
+		if len(cursor) != node.AddrTxCursorSize {
+			return nil, invalidParams("[GetAddressHistory] invalid cursor %s", hex)
+		}
+	}
+
+	assetId := node.AssetEla
+	if hex, ok := params.String("assetid"); ok {
+		id, err := uint256FromHex(hex)
+		if err != nil {
+			return nil, invalidParams("[GetAddressHistory] parse assetid failed %s", err.Error())
+		}
+		assetId = *id
+	}
+
+	addrTxs, err := Node.GetAddrTxs(programHash, cursor, int(skip), int(count), reverse)
+	if err != nil {
+		return nil, fmt.Errorf("[GetAddressHistory] query address history failed %s", err.Error())
+	}
+
+	history := AddrHistoryInfo{
+		Address:      address,
+		Transactions: make([]AddrTxInfo, 0, len(addrTxs)),
+	}
+	for _, addrTx := range addrTxs {
+		direction := "received"
+		delta := addrTx.Delta(assetId)
+		amount := delta.String()
+		if delta < 0 {
+			direction = "sent"
+			amount = "-" + (-delta).String()
+		}
+		history.Transactions = append(history.Transactions, AddrTxInfo{
+			TxID:          common.BytesToHexString(addrTx.TxID.Bytes()),
+			Direction:     direction,
+			Amount:        amount,
+			Height:        addrTx.Height,
+			Confirmations: getConfirmations(addrTx.Height),
+		})
+	}
+	if len(addrTxs) > 0 && len(addrTxs) == int(count) {
+		history.Cursor = common.BytesToHexString(addrTxs[len(addrTxs)-1].Cursor())
+	}
+	return history, nil
 }
 
 func getBlock(hash *common.Uint256, format uint32) (Result, error) {
@@ -229,16 +864,47 @@ func getBlock(hash *common.Uint256, format uint32) (Result, error) {
 	}
 	switch format {
 	case 0:
-		return nil, fmt.Errorf("[GetBlock] serialized format not support in SPV node")
+		data, err := serializeBlock(hash)
+		if err != nil {
+			return nil, err
+		}
+		return common.BytesToHexString(data), nil
 	case 2:
 		return getBlockInfo(storeHeader.Header, true)
 	}
 	return getBlockInfo(storeHeader.Header, false)
 }
 
-func getBlockInfo(header core.Header, verbose bool) (*BlockInfo, error) {
-	hash := header.Hash()
+// serializeBlock serializes the block header with the transactions stored,
+// which are the transactions matched the registered addresses.
+func serializeBlock(hash *common.Uint256) ([]byte, error) {
+	storeHeader, err := Node.GetHeader(hash)
+	if err != nil {
+		return nil, fmt.Errorf("[GetBlock] unknown block with hash %s", hash.String())
+	}
 
+	block := core.Block{Header: storeHeader.Header}
+	txIds, err := Node.GetTxIds(storeHeader.Height)
+	if err != nil {
+		return nil, fmt.Errorf("[GetBlock] query block transactions failed %s", err.Error())
+	}
+	for _, txId := range txIds {
+		tx, err := Node.GetTx(txId)
+		if err != nil {
+			return nil, fmt.Errorf("[GetBlock] query transaction %s failed %s",
+				txId.String(), err.Error())
+		}
+		block.Transactions = append(block.Transactions, &tx.Transaction)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := block.Serialize(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func getBlockInfo(header core.Header, verbose bool) (*BlockInfo, error) {
 	txIds, err := Node.GetTxIds(header.Height)
 	if err != nil {
 		return nil, fmt.Errorf("[GetBlockInfo] query block transactions failed %s", err.Error())
@@ -258,53 +924,108 @@ func getBlockInfo(header core.Header, verbose bool) (*BlockInfo, error) {
 			txs = append(txs, common.BytesToHexString(txId.Bytes()))
 		}
 	}
+	info, err := getHeaderInfo(&header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockInfo{HeaderInfo: info, Tx: txs}, nil
+}
+
+func getHeaderInfo(header *core.Header) (*HeaderInfo, error) {
+	hash := header.Hash()
+
 	var versionBytes [4]byte
 	binary.BigEndian.PutUint32(versionBytes[:], header.Version)
 
-	var chainWork [4]byte
-	binary.BigEndian.PutUint32(chainWork[:], Node.BestHeight()-header.Height)
+	storeHeader, err := Node.GetHeader(&hash)
+	if err != nil {
+		return nil, fmt.Errorf("[GetHeaderInfo] query header %s failed %s", hash.String(), err.Error())
+	}
 
-	nextBlockHash, err := Node.GetHeaderHash(header.Height + 1)
+	medianTime, err := Node.GetMedianTimePast(header)
 	if err != nil {
-		nextBlockHash = new(common.Uint256)
+		return nil, fmt.Errorf("[GetHeaderInfo] query median time failed %s", err.Error())
+	}
+
+	// Headers above the best height may be left by rescan or rollback
+	var nextBlockHash string
+	if header.Height < Node.BestHeight() {
+		if next, err := Node.GetHeaderHash(header.Height + 1); err == nil {
+			nextBlockHash = next.String()
+		}
 	}
 
 	auxPow := new(bytes.Buffer)
 	header.AuxPow.Serialize(auxPow)
 
-	return &BlockInfo{
+	return &HeaderInfo{
 		Hash:              hash.String(),
-		Confirmations:     Node.BestHeight() - header.Height + 1,
-		StrippedSize:      0,
-		Size:              0,
-		Weight:            0,
+		Confirmations:     getConfirmations(header.Height),
 		Height:            header.Height,
 		Version:           header.Version,
 		VersionHex:        common.BytesToHexString(versionBytes[:]),
 		MerkleRoot:        header.MerkleRoot.String(),
-		Tx:                txs,
 		Time:              header.Timestamp,
-		MedianTime:        header.Timestamp,
+		MedianTime:        medianTime,
 		Nonce:             header.Nonce,
 		Bits:              header.Bits,
-		Difficulty:        "",
-		ChainWork:         common.BytesToHexString(chainWork[:]),
+		Difficulty:        node.CalcDifficulty(header.Bits).Text('f', 8),
+		ChainWork:         getChainWork(storeHeader.TotalWork),
 		PreviousBlockHash: header.Previous.String(),
-		NextBlockHash:     nextBlockHash.String(),
+		NextBlockHash:     nextBlockHash,
 		AuxPow:            common.BytesToHexString(auxPow.Bytes()),
 	}, nil
 }
 
+func getRescanInfo(info *node.RescanInfo) *RescanInfo {
+	var progress float64 = 1
+	if info.TargetHeight >= info.StartHeight {
+		total := info.TargetHeight - info.StartHeight + 1
+		done := info.CurrentHeight + 1 - info.StartHeight
+		progress = float64(done) / float64(total)
+	}
+
+	addresses := make([]string, 0, len(info.Addrs))
+	for _, addr := range info.Addrs {
+		address, _ := addr.ToAddress()
+		addresses = append(addresses, address)
+	}
+
+	return &RescanInfo{
+		InProgress:    info.InProgress,
+		StartHeight:   info.StartHeight,
+		CurrentHeight: info.CurrentHeight,
+		TargetHeight:  info.TargetHeight,
+		Progress:      progress,
+		Addresses:     addresses,
+	}
+}
+
 func getTransactionInfo(header *core.Header, tx *core.Transaction) *TransactionInfo {
 	inputs := make([]InputInfo, len(tx.Inputs))
+	inputValues := make(map[common.Uint256]common.Fixed64)
+	prevOutsKnown := !tx.IsCoinBaseTx()
 	for i, v := range tx.Inputs {
 		inputs[i].TxID = common.BytesToHexString(v.Previous.TxID.Bytes())
 		inputs[i].VOut = v.Previous.Index
 		inputs[i].Sequence = v.Sequence
+
+		prevOut := getPrevOut(&v.Previous)
+		if prevOut == nil {
+			prevOutsKnown = false
+			continue
+		}
+		inputs[i].Address, _ = prevOut.ProgramHash.ToAddress()
+		inputs[i].Value = prevOut.Value.String()
+		inputs[i].AssetID = common.BytesToHexString(prevOut.AssetID.Bytes())
+		inputValues[prevOut.AssetID] += prevOut.Value
 	}
 
 	outputs := make([]OutputInfo, len(tx.Outputs))
+	outputValues := make(map[common.Uint256]common.Fixed64)
 	for i, v := range tx.Outputs {
+		outputValues[v.AssetID] += v.Value
 		outputs[i].Value = v.Value.String()
 		outputs[i].Index = uint32(i)
 		address, _ := v.ProgramHash.ToAddress()
@@ -317,6 +1038,7 @@ func getTransactionInfo(header *core.Header, tx *core.Transaction) *TransactionI
 	for i, v := range tx.Attributes {
 		attributes[i].Usage = v.Usage
 		attributes[i].Data = common.BytesToHexString(v.Data)
+		attributes[i].Value = getAttributeValue(v)
 	}
 
 	programs := make([]ProgramInfo, len(tx.Programs))
@@ -325,6 +1047,19 @@ func getTransactionInfo(header *core.Header, tx *core.Transaction) *TransactionI
 		programs[i].Parameter = common.BytesToHexString(v.Parameter)
 	}
 
+	// Fees can only be computed when all the previous outputs are known
+	var fees []FeeInfo
+	if prevOutsKnown {
+		for assetId, value := range inputValues {
+			fee := value - outputValues[assetId]
+			fees = append(fees, FeeInfo{
+				AssetID: common.BytesToHexString(assetId.Bytes()),
+				Fee:     fee.String(),
+			})
+		}
+		sort.Slice(fees, func(i, j int) bool { return fees[i].AssetID < fees[j].AssetID })
+	}
+
 	var txHash = tx.Hash()
 	var txHashStr = txHash.String()
 	var size = uint32(tx.GetSize())
@@ -339,15 +1074,162 @@ func getTransactionInfo(header *core.Header, tx *core.Transaction) *TransactionI
 		Inputs:         inputs,
 		Outputs:        outputs,
 		BlockHash:      header.Hash().String(),
-		Confirmations:  Node.BestHeight() - header.Height + 1,
+		Confirmations:  getConfirmations(header.Height),
 		Time:           header.Timestamp,
 		BlockTime:      header.Timestamp,
 		TxType:         tx.TxType,
 		PayloadVersion: tx.PayloadVersion,
-		Payload:        nil,
+		Payload:        getPayloadInfo(tx.Payload),
 		Attributes:     attributes,
 		Programs:       programs,
+		Fees:           fees,
+	}
+}
+
+// getVerboseTransaction returns the transaction info, and marks whether
+// the previous output of each input is known in prevout lookup mode.
+func getVerboseTransaction(header *core.Header, tx *core.Transaction, params Params) *TransactionInfo {
+	info := getTransactionInfo(header, tx)
+	if prevOut, ok := params.Bool("prevout"); ok && prevOut && !tx.IsCoinBaseTx() {
+		for i := range info.Inputs {
+			if len(info.Inputs[i].Value) > 0 {
+				info.Inputs[i].PrevOut = "found"
+			} else {
+				info.Inputs[i].PrevOut = "unknown"
+			}
+		}
+	}
+	return info
+}
+
+// getPrevOut returns the output referenced by the outpoint from the stored
+// transactions, nil if the transaction is not stored.
+func getPrevOut(op *core.OutPoint) *core.Output {
+	tx, err := Node.GetTx(&op.TxID)
+	if err != nil || int(op.Index) >= len(tx.Outputs) {
+		return nil
+	}
+	return tx.Outputs[op.Index]
+}
+
+// getAttributeValue decodes the attribute data by usage, returns nil if the
+// data is not in the format of the usage.
+func getAttributeValue(attr *core.Attribute) interface{} {
+	switch attr.Usage {
+	case core.Nonce:
+		// Nonces are decimal strings written by ELA wallets and this node,
+		// others are shown in string if printable, otherwise in hex string
+		if nonce, err := strconv.ParseUint(string(attr.Data), 10, 64); err == nil {
+			return nonce
+		}
+		if isPrintable(attr.Data) {
+			return string(attr.Data)
+		}
+		return common.BytesToHexString(attr.Data)
+	case core.Memo, core.Description, core.DescriptionUrl:
+		if !utf8.Valid(attr.Data) {
+			return nil
+		}
+		return string(attr.Data)
+	case core.Script:
+		programHash, err := common.Uint168FromBytes(attr.Data)
+		if err != nil {
+			return nil
+		}
+		address, err := programHash.ToAddress()
+		if err != nil {
+			return nil
+		}
+		return address
+	}
+	return nil
+}
+
+func isPrintable(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !strconv.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// getPayloadInfo decodes the transaction payload in the same format as
+// the ELA full node.
+func getPayloadInfo(payload core.Payload) PayloadInfo {
+	switch p := payload.(type) {
+	case *core.PayloadCoinBase:
+		return &CoinbaseInfo{CoinbaseData: string(p.CoinbaseData)}
+	case *core.PayloadRegisterAsset:
+		return &RegisterAssetInfo{
+			Asset:      p.Asset,
+			Amount:     p.Amount.String(),
+			Controller: common.BytesToHexString(common.BytesReverse(p.Controller.Bytes())),
+		}
+	case *core.PayloadTransferAsset:
+		return &TransferAssetInfo{}
+	case *core.PayloadRecord:
+		return &RecordInfo{
+			RecordType: p.RecordType,
+			RecordData: common.BytesToHexString(p.RecordData),
+		}
+	case *core.PayloadSideChainPow:
+		return &SideChainPowInfo{
+			BlockHeight:     p.BlockHeight,
+			SideBlockHash:   p.SideBlockHash.String(),
+			SideGenesisHash: p.SideGenesisHash.String(),
+			SignedData:      common.BytesToHexString(p.SignedData),
+		}
+	case *core.PayloadRechargeToSideChain:
+		return &RechargeToSideChainInfo{
+			Proof:                common.BytesToHexString(p.MerkleProof),
+			MainChainTransaction: common.BytesToHexString(p.MainChainTransaction),
+		}
+	case *core.PayloadWithdrawFromSideChain:
+		hashes := make([]string, 0, len(p.SideChainTransactionHashes))
+		for _, hash := range p.SideChainTransactionHashes {
+			hashes = append(hashes, hash.String())
+		}
+		return &WithdrawFromSideChainInfo{
+			BlockHeight:                p.BlockHeight,
+			GenesisBlockAddress:        p.GenesisBlockAddress,
+			SideChainTransactionHashes: hashes,
+		}
+	case *core.PayloadTransferCrossChainAsset:
+		return &TransferCrossChainAssetInfo{
+			CrossChainAddresses: p.CrossChainAddresses,
+			OutputIndexes:       p.OutputIndexes,
+			CrossChainAmounts:   p.CrossChainAmounts,
+		}
+	}
+	return nil
+}
+
+// getChainWork returns the cumulative chain work in 64 hex digits.
+func getChainWork(totalWork *big.Int) string {
+	if totalWork == nil {
+		totalWork = new(big.Int)
+	}
+	return fmt.Sprintf("%064x", totalWork)
+}
+
+func getConfirmations(height uint32) uint32 {
+	bestHeight := Node.BestHeight()
+	if height > bestHeight {
+		return 0
+	}
+	return bestHeight - height + 1
+}
+
+func uint256FromHex(hex string) (*common.Uint256, error) {
+	data, err := common.HexStringToBytes(hex)
+	if err != nil {
+		return nil, err
 	}
+	return common.Uint256FromBytes(data)
 }
 
 func elaTxToBtcTx(elaTx *core.Transaction) *auxpow.BtcTx {
@@ -0,0 +1,28 @@
+package rpc
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+func TestGetAttributeValue(t *testing.T) {
+	cases := []struct {
+		attr  core.Attribute
+		value interface{}
+	}{
+		{core.NewAttribute(core.Nonce, []byte(strconv.FormatInt(8784902185364663223, 10))),
+			uint64(8784902185364663223)},
+		{core.NewAttribute(core.Nonce, []byte("nonce-1")), "nonce-1"},
+		{core.NewAttribute(core.Nonce, []byte{0xb7, 0xab, 0x36, 0x41, 0x00}), "b7ab364100"},
+		{core.NewAttribute(core.Memo, []byte("memo")), "memo"},
+		{core.NewAttribute(core.Memo, []byte{0xff}), nil},
+	}
+	for _, c := range cases {
+		if value := getAttributeValue(&c.attr); value != c.value {
+			t.Fatalf("attribute %d %x got value %v, expect %v",
+				c.attr.Usage, c.attr.Data, value, c.value)
+		}
+	}
+}
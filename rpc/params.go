@@ -102,3 +102,24 @@ func (p Params) String(key string) (string, bool) {
 		return "", false
 	}
 }
+
+func (p Params) Strings(key string) ([]string, bool) {
+	value, ok := p[key]
+	if !ok {
+		return nil, false
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		strings := make([]string, 0, len(v))
+		for _, s := range v {
+			str, ok := s.(string)
+			if !ok {
+				return nil, false
+			}
+			strings = append(strings, str)
+		}
+		return strings, true
+	default:
+		return nil, false
+	}
+}
@@ -1,5 +1,10 @@
 package rpc
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 const (
 	// JSON-RPC protocol error codes.
 	ParseError     = -32700
@@ -7,7 +12,8 @@ const (
 	MethodNotFound = -32601
 	InvalidParams  = -32602
 	InternalError  = -32603
-	//-32000 to -32099	Server error, waiting for defining
+	//-32000 to -32099	Server error
+	Forbidden = -32001
 )
 
 type Method func(Params) (Result, error)
@@ -16,22 +22,52 @@ type Result interface{}
 
 type MethodMap map[string]Method
 
+// Request id can be a number, a string or null, it is kept as the raw
+// JSON value and returned as is in the response. A JSON-RPC 2.0 request
+// without id is a notification, which will not be responded.
 type Request struct {
-	Id      uint32      `json:"id,omitempty"`
-	Version string      `json:"jsonrpc,omitempty"`
-	Method  string      `json:"method"`
-	Params  interface{} `json:"params"`
+	Id      json.RawMessage `json:"id,omitempty"`
+	Version string          `json:"jsonrpc,omitempty"`
+	Method  string          `json:"method"`
+	Params  interface{}     `json:"params"`
 }
 
+func (r *Request) IsNotification() bool {
+	return r.Version == "2.0" && len(r.Id) == 0
+}
+
+// Response id is always present, it is null if the request id can not be
+// read, like the request failed to parse.
 type Response struct {
-	Id      uint32 `json:"id,omitempty"`
-	Version string `json:"jsonrpc,omitempty"`
-	Result  Result `json:"result,omitempty"`
-	Error   *Error `json:"error,omitempty"`
+	Id      json.RawMessage `json:"id"`
+	Version string          `json:"jsonrpc,omitempty"`
+	Result  Result          `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
 }
 
 type Error struct {
-	Id      uint32 `json:"id,omitempty"`
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// invalidParams returns an error which will be responded with the
+// InvalidParams code instead of InternalError.
+func invalidParams(format string, a ...interface{}) error {
+	return &Error{Code: InvalidParams, Message: fmt.Sprintf(format, a...)}
+}
+
+type Notification struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type SubscriptionResult struct {
+	Subscription string      `json:"subscription"`
+	Event        string      `json:"event"`
+	Result       interface{} `json:"result"`
+}
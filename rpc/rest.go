@@ -0,0 +1,206 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+	"github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+const (
+	// Response encodings of REST API.
+	RESTFormatJSON = "json"
+	RESTFormatHex  = "hex"
+	RESTFormatBin  = "bin"
+)
+
+// restHandler returns the JSON result, or the serialized bytes for hex and
+// bin encodings. The method is the mirrored JSON-RPC method.
+type restHandler func(method Method, args []string, format string, query Params) (Result, error)
+
+type restRoute struct {
+	// method is the mirrored JSON-RPC method, the route shares it's
+	// method allowlist.
+	method  string
+	path    string
+	raw     bool
+	handler restHandler
+}
+
+// restRoutes are the routes by the first path segment, path of the route
+// is the pattern of the rest segments.
+var restRoutes = map[string]restRoute{
+	"block":       {method: "getblock", path: "{hash}", raw: true, handler: restBlock},
+	"blockheight": {method: "getblockbyheight", path: "{height}", raw: true, handler: restBlockHeight},
+	"tx":          {method: "getrawtransaction", path: "{txid}", raw: true, handler: restTx},
+	"address":     {method: "listunspent", path: "{address}/utxos", handler: restUTXOs},
+	"chaininfo":   {method: "getblockchaininfo", handler: restChainInfo},
+}
+
+func HandleREST(w http.ResponseWriter, r *http.Request) {
+	credential, ok := checkAuth(w, r)
+	if !ok {
+		return
+	}
+	handleREST(w, r, credential)
+}
+
+// handleREST serves the read-only REST API, a path is in the form of
+// /rest/{route}/{args...}.{format}, the format is json if not specified.
+func handleREST(w http.ResponseWriter, r *http.Request, credential *credential) {
+	if r.Method != "GET" {
+		http.Error(w, "REST API only allows GET method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/rest/")
+	format := RESTFormatJSON
+	if i := strings.LastIndex(path, "."); i >= 0 && !strings.Contains(path[i:], "/") {
+		path, format = path[:i], path[i+1:]
+	}
+
+	segments := strings.Split(path, "/")
+	route, ok := restRoutes[segments[0]]
+	if !ok {
+		http.Error(w, "unknown path "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	args, ok := matchPath(route.path, segments[1:])
+	if !ok {
+		http.Error(w, "unknown path "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+
+	switch format {
+	case RESTFormatJSON:
+	case RESTFormatHex, RESTFormatBin:
+		if !route.raw {
+			http.Error(w, "format "+format+" not supported", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "unknown format "+format+", must be json, hex or bin", http.StatusBadRequest)
+		return
+	}
+
+	method, ok := authorize(credential, r.RemoteAddr, lookupMethod)(route.method)
+	if !ok {
+		http.Error(w, "method "+route.method+" not found", http.StatusNotFound)
+		return
+	}
+
+	query := Params{}
+	for key, values := range r.URL.Query() {
+		query[key] = values[0]
+	}
+	result, err := route.handler(method, args, format, query)
+	if err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	switch format {
+	case RESTFormatJSON:
+		data, err := json.Marshal(result)
+		if err != nil {
+			writeRESTError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	case RESTFormatHex:
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(common.BytesToHexString(result.([]byte)) + "\n"))
+	case RESTFormatBin:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(result.([]byte))
+	}
+}
+
+// matchPath matches the segments with the path pattern, returns the
+// segments in place of the {} placeholders.
+func matchPath(pattern string, segments []string) ([]string, bool) {
+	var parts []string
+	if len(pattern) > 0 {
+		parts = strings.Split(pattern, "/")
+	}
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+
+	var args []string
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") {
+			args = append(args, segments[i])
+		} else if part != segments[i] {
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+func restBlock(method Method, args []string, format string, query Params) (Result, error) {
+	if format == RESTFormatJSON {
+		return method(Params{"hash": args[0], "format": "2"})
+	}
+	return restSerialized(method, Params{"hash": args[0], "format": "0"})
+}
+
+func restBlockHeight(method Method, args []string, format string, query Params) (Result, error) {
+	if format == RESTFormatJSON {
+		return method(Params{"height": args[0], "format": "2"})
+	}
+	return restSerialized(method, Params{"height": args[0], "format": "0"})
+}
+
+func restTx(method Method, args []string, format string, query Params) (Result, error) {
+	if format == RESTFormatJSON {
+		return method(Params{"hash": args[0], "format": "json"})
+	}
+	return restSerialized(method, Params{"hash": args[0], "format": "ela"})
+}
+
+// restSerialized calls the method for the serialized result in hex string,
+// and decodes it for the hex and bin encodings.
+func restSerialized(method Method, params Params) (Result, error) {
+	result, err := method(params)
+	if err != nil {
+		return nil, err
+	}
+	hex, ok := result.(string)
+	if !ok {
+		return nil, errors.New("[REST] unexpected serialized result")
+	}
+	return common.HexStringToBytes(hex)
+}
+
+func restUTXOs(method Method, args []string, format string, query Params) (Result, error) {
+	params := Params{"addresses": []interface{}{args[0]}, "minconf": "0"}
+	for _, key := range []string{"minconf", "maxconf", "assetid"} {
+		if value, ok := query[key]; ok {
+			params[key] = value
+		}
+	}
+	return method(params)
+}
+
+func restChainInfo(method Method, args []string, format string, query Params) (Result, error) {
+	return method(Params{})
+}
+
+func writeRESTError(w http.ResponseWriter, err error) {
+	status := http.StatusNotFound
+	if e, ok := err.(*Error); ok {
+		switch e.Code {
+		case InvalidParams:
+			status = http.StatusBadRequest
+		case Forbidden:
+			status = http.StatusForbidden
+		}
+	}
+	log.Debug("[REST] request error ", err)
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,28 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRESTAuthorize(t *testing.T) {
+	initMethods()
+
+	// The serialized encodings share the allowlist of the mirrored method
+	restricted := &credential{name: "reader", methods: map[string]struct{}{"listunspent": {}}}
+	hash := "5f4d138e9318d1e25600d5141628bf288acacd53e78e0ac9976938bfc69088cf"
+	for _, path := range []string{
+		"/rest/block/" + hash + ".json",
+		"/rest/block/" + hash + ".hex",
+		"/rest/block/" + hash + ".bin",
+		"/rest/blockheight/10.bin",
+		"/rest/tx/" + hash + ".hex",
+	} {
+		w := httptest.NewRecorder()
+		handleREST(w, httptest.NewRequest("GET", path, nil), restricted)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("%s got status %d, expect %d", path, w.Code, http.StatusForbidden)
+		}
+	}
+}
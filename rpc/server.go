@@ -1,10 +1,14 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/elastos/Elastos.ELA.SPV.Node/config"
 	"github.com/elastos/Elastos.ELA.SPV.Node/node"
@@ -12,6 +16,10 @@ import (
 	"github.com/elastos/Elastos.ELA.SPV/log"
 )
 
+// MaxBatchWorkers is the maximum number of requests in a batch executed
+// concurrently.
+const MaxBatchWorkers = 16
+
 var Node *node.SPVNode
 var methods MethodMap
 
@@ -19,27 +27,88 @@ func initMethods() {
 	methods = make(MethodMap)
 	methods["registeraddresses"] = RegisterAddresses
 	methods["registeraddress"] = RegisterAddress
+	methods["unregisteraddresses"] = UnregisterAddresses
+	methods["unregisteraddress"] = UnregisterAddress
+	methods["getaddresses"] = GetAddresses
+	methods["rescan"] = Rescan
+	methods["getrescaninfo"] = GetRescanInfo
+	methods["getblockchaininfo"] = GetBlockchainInfo
+	methods["getchaintips"] = GetChainTips
+	methods["getpeerinfo"] = GetPeerInfo
+	methods["getconnectioncount"] = GetConnectionCount
+	methods["getnetworkinfo"] = GetNetworkInfo
+	methods["addnode"] = AddNode
+	methods["disconnectnode"] = DisconnectNode
+	methods["setban"] = SetBan
+	methods["listbanned"] = ListBanned
+	methods["clearbanned"] = ClearBanned
 	methods["getblockcount"] = GetBlockCount
 	methods["getbestblockhash"] = GetBestBlockHash
 	methods["getblockhash"] = GetBlockHash
+	methods["getblockheader"] = GetBlockHeader
 	methods["getblock"] = GetBlock
 	methods["getblockbyheight"] = GetBlockByHeight
 	methods["getrawtransaction"] = GetRawTransaction
 	methods["sendrawtransaction"] = SendRawTransaction
+	methods["gettxoutproof"] = GetTxOutProof
+	methods["verifytxoutproof"] = VerifyTxOutProof
+	methods["listunspent"] = ListUnspent
+	methods["getbalance"] = GetBalance
+	methods["getreceivedbyaddress"] = GetReceivedByAddress
+	methods["getaddresshistory"] = GetAddressHistory
 }
 
 func StartServer(spvNode *node.SPVNode) {
-	log.Debug("Start RPC server at port:", config.Values().RPCPort)
 	Node = spvNode
 	initMethods()
+	auth = newAuthenticator(config.Values().RPCUsers)
+	Node.AddListener(hub)
+
+	if unix := config.Values().RPCUnix; unix != nil {
+		if err := startUnixServer(unix); err != nil {
+			log.Error("Start RPC server on unix socket error: ", err.Error())
+		}
+	}
+
+	// Unix socket only if RPC port not specified
+	if config.Values().RPCPort == 0 {
+		return
+	}
+
+	log.Debug("Start RPC server at port:", config.Values().RPCPort)
+	http.HandleFunc("/ws", HandleWebSocket)
+	http.HandleFunc("/rest/", HandleREST)
 	http.HandleFunc("/", Handle)
-	err := http.ListenAndServe(":"+strconv.Itoa(config.Values().RPCPort), nil)
+	address := net.JoinHostPort(config.Values().RPCBind, strconv.Itoa(config.Values().RPCPort))
+	tlsConfig, err := TLSConfig()
 	if err != nil {
-		log.Error("ListenAndServe: ", err.Error())
+		log.Error("RPC server TLS config error: ", err.Error())
+		return
+	}
+	if tlsConfig == nil {
+		err := http.ListenAndServe(address, nil)
+		if err != nil {
+			log.Error("ListenAndServe: ", err.Error())
+		}
+		return
+	}
+
+	server := &http.Server{Addr: address, TLSConfig: tlsConfig}
+	err = server.ListenAndServeTLS("", "")
+	if err != nil {
+		log.Error("ListenAndServeTLS: ", err.Error())
 	}
 }
 
 func Handle(w http.ResponseWriter, r *http.Request) {
+	credential, ok := checkAuth(w, r)
+	if !ok {
+		return
+	}
+	handleHTTP(w, r, credential)
+}
+
+func handleHTTP(w http.ResponseWriter, r *http.Request, credential *credential) {
 	//JSON RPC commands should be POSTs
 	if r.Method != "POST" {
 		log.Warn("HTTP JSON RPC Handle - Method!=\"POST\"")
@@ -47,97 +116,223 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.Header["Content-Type"][0] != "application/json" {
-		log.Warn("HTTP JSON RPC Handle - Content-Type: ", r.Header["Content-Type"][0], " not supported")
+	contentType := r.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/json") {
+		log.Warn("HTTP JSON RPC Handle - Content-Type: ", contentType, " not supported")
 		http.Error(w, "need content type to be application/json", http.StatusUnsupportedMediaType)
 		return
 	}
 
 	//read the body of the request
 	body, _ := ioutil.ReadAll(r.Body)
-	var request Request
-	var response Response
-	error := json.Unmarshal(body, &request)
-	if error != nil {
-		log.Warn("HTTP JSON RPC Handle - json.Unmarshal: ", error)
-		response.WriteError(w, http.StatusBadRequest, ParseError, "rpc json parse error:"+error.Error())
+	response, httpStatus := handleBody(body, authorize(credential, r.RemoteAddr, lookupMethod))
+	if response == nil {
+		w.WriteHeader(httpStatus)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Content-Type", "charset=utf-8")
+	w.WriteHeader(httpStatus)
+	data, _ := json.Marshal(response)
+	w.Write(data)
+}
+
+func lookupMethod(name string) (Method, bool) {
+	method, ok := methods[name]
+	return method, ok
+}
+
+// handleBody handles a single request or a batch of requests, returns the
+// response to write and the HTTP status. The response is nil when there
+// is nothing to respond, which means all requests are notifications.
+func handleBody(body []byte, lookup func(string) (Method, bool)) (interface{}, int) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		response := handleRequest(body, lookup)
+		if response == nil {
+			return nil, http.StatusNoContent
+		}
+		return response, httpStatus(response)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		log.Warn("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+		return newErrorResponse(ParseError, "rpc json parse error:"+err.Error()), http.StatusBadRequest
+	}
+	if len(batch) == 0 {
+		return newErrorResponse(InvalidRequest, "empty batch"), http.StatusBadRequest
+	}
+
+	// Execute the requests concurrently and respond in the request order
+	results := make([]*Response, len(batch))
+	workers := make(chan struct{}, MaxBatchWorkers)
+	var wg sync.WaitGroup
+	for i, data := range batch {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, data []byte) {
+			results[i] = handleRequest(data, lookup)
+			<-workers
+			wg.Done()
+		}(i, data)
+	}
+	wg.Wait()
+
+	responses := make([]*Response, 0, len(results))
+	for _, response := range results {
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		return nil, http.StatusNoContent
+	}
+	return responses, http.StatusOK
+}
+
+// handleRequest executes a single request, the response is nil if the
+// request is a notification.
+func handleRequest(data []byte, lookup func(string) (Method, bool)) *Response {
+	var request Request
+	if err := json.Unmarshal(data, &request); err != nil {
+		log.Warn("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+		if json.Valid(data) {
+			return newErrorResponse(InvalidRequest, "request must be an object")
+		}
+		return newErrorResponse(ParseError, "rpc json parse error:"+err.Error())
+	}
+
+	response := execute(&request, lookup)
+	if request.IsNotification() {
+		return nil
+	}
 	response.Id = request.Id
 	response.Version = request.Version
+	return response
+}
 
+func execute(request *Request, lookup func(string) (Method, bool)) *Response {
 	if len(request.Method) == 0 {
-		response.WriteError(w, http.StatusBadRequest, InvalidRequest, "need a method!")
-		return
+		return newErrorResponse(InvalidRequest, "need a method!")
 	}
-	method, ok := methods[request.Method]
+	method, ok := lookup(request.Method)
 	if !ok {
-		response.WriteError(w, http.StatusNotFound, MethodNotFound, "method "+request.Method+" not found")
-		return
+		return newErrorResponse(MethodNotFound, "method "+request.Method+" not found")
 	}
 
-	// Json rpc 1.0 support positional parameters while json rpc 2.0 support named parameters.
-	// positional parameters: { "params":[1, 2, 3....] }
-	// named parameters: { "params":{ "a":1, "b":2, "c":3 } }
-	// Here we support both of them, just like bitcion does.
-	var params Params
-	switch requestParams := request.Params.(type) {
-	case nil:
-	case []interface{}:
-		params = formatParams(request.Method, requestParams)
-	case map[string]interface{}:
-		params = Params(requestParams)
-	default:
-		response.WriteError(w, http.StatusBadRequest, InvalidRequest, "params format error, must be an array or a map")
-		return
+	params, ok := parseParams(request)
+	if !ok {
+		return newErrorResponse(InvalidParams, "params format error, must be an array or a map")
 	}
 
 	result, err := method(params)
 	if err != nil {
-		response.WriteError(w, http.StatusInternalServerError, InternalError, "internal error: "+err.Error())
-		return
+		if e, ok := err.(*Error); ok {
+			if e.Code == InvalidParams {
+				return newErrorResponse(InvalidParams, "invalid params: "+e.Message)
+			}
+			return newErrorResponse(e.Code, e.Message)
+		}
+		return newErrorResponse(InternalError, "internal error: "+err.Error())
 	}
 
-	response.Result = result
-	response.Write(w)
+	return &Response{Result: result}
 }
 
-func (r *Response) WriteError(w http.ResponseWriter, httpStatus, code int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Add("Content-Type", "charset=utf-8")
-	w.WriteHeader(httpStatus)
-	r.Error = new(Error)
-	r.Error.Code = code
-	r.Error.Message = message
-	data, _ := json.Marshal(r)
-	w.Write(data)
+func newErrorResponse(code int, message string) *Response {
+	return &Response{Error: &Error{Code: code, Message: message}}
 }
 
-func (r *Response) Write(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Add("Content-Type", "charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	data, _ := json.Marshal(r)
-	w.Write(data)
+func httpStatus(response *Response) int {
+	if response.Error == nil {
+		return http.StatusOK
+	}
+	switch response.Error.Code {
+	case ParseError, InvalidRequest, InvalidParams:
+		return http.StatusBadRequest
+	case MethodNotFound:
+		return http.StatusNotFound
+	case Forbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Json rpc 1.0 support positional parameters while json rpc 2.0 support named parameters.
+// positional parameters: { "params":[1, 2, 3....] }
+// named parameters: { "params":{ "a":1, "b":2, "c":3 } }
+// Here we support both of them, just like bitcion does.
+func parseParams(request *Request) (Params, bool) {
+	switch requestParams := request.Params.(type) {
+	case nil:
+		return Params{}, true
+	case []interface{}:
+		return formatParams(request.Method, requestParams), true
+	case map[string]interface{}:
+		return Params(requestParams), true
+	default:
+		return nil, false
+	}
 }
 
 func formatParams(method string, params []interface{}) Params {
 	switch method {
 	case "registeraddresses":
-		return FromArray(params, "addresses")
+		return FromArray(params, "addresses", "birthday")
 	case "registeraddress":
+		return FromArray(params, "address", "label", "birthday")
+	case "unregisteraddresses":
+		return FromArray(params, "addresses", "purge")
+	case "unregisteraddress":
+		return FromArray(params, "address", "purge")
+	case "rescan":
+		return FromArray(params, "startheight", "addresses")
+	case "subscribe":
+		if len(params) > 0 && params[0] == EventConfirmation {
+			return FromArray(params, "event", "confirmations")
+		}
+		return FromArray(params, "event", "addresses")
+	case "unsubscribe":
+		return FromArray(params, "id")
+	case "addnode":
+		return FromArray(params, "node", "command")
+	case "disconnectnode":
 		return FromArray(params, "address")
+	case "setban":
+		return FromArray(params, "address", "command", "bantime", "absolute")
 	case "getblockhash":
 		return FromArray(params, "index")
+	case "getblockheader":
+		// The block can be specified by hash or height
+		if len(params) > 0 {
+			if _, ok := params[0].(float64); ok {
+				return FromArray(params, "height", "verbose")
+			}
+		}
+		return FromArray(params, "hash", "verbose")
 	case "getblock":
 		return FromArray(params, "hash", "format")
 	case "getblockbyheight":
 		return FromArray(params, "height", "format")
 	case "getrawtransaction":
-		return FromArray(params, "hash", "format")
+		return FromArray(params, "hash", "format", "prevout")
 	case "sendrawtransaction":
 		return FromArray(params, "data", "format")
+	case "gettxoutproof":
+		return FromArray(params, "txid")
+	case "verifytxoutproof":
+		return FromArray(params, "proof")
+	case "listunspent":
+		return FromArray(params, "minconf", "maxconf", "addresses", "assetid")
+	case "getbalance":
+		return FromArray(params, "address", "minconf", "assetid")
+	case "getreceivedbyaddress":
+		return FromArray(params, "address", "minconf", "assetid")
+	case "getaddresshistory":
+		return FromArray(params, "address", "count", "skip", "order", "cursor", "assetid")
 	default:
 		return Params{}
 	}
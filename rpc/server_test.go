@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func testLookup(name string) (Method, bool) {
+	if name != "echo" {
+		return nil, false
+	}
+	return func(params Params) (Result, error) {
+		value, ok := params.String("value")
+		if !ok {
+			return nil, invalidParams("[Echo] parameter value not exist")
+		}
+		return value, nil
+	}, true
+}
+
+func marshalTestResponse(t *testing.T, response interface{}) string {
+	if response == nil {
+		return ""
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHandleBody(t *testing.T) {
+	cases := []struct {
+		body     string
+		response string
+		status   int
+	}{
+		{`{"id":1,"method":"echo","params":{"value":"a"}}`,
+			`{"id":1,"result":"a"}`, http.StatusOK},
+		{`{"jsonrpc":"2.0","id":"x","method":"echo","params":{}}`,
+			`{"id":"x","jsonrpc":"2.0","error":{"code":-32602,"message":"invalid params: [Echo] parameter value not exist"}}`,
+			http.StatusBadRequest},
+		{`{"id":1,"method":`,
+			`{"id":null,"error":{"code":-32700,"message":"rpc json parse error:unexpected end of JSON input"}}`,
+			http.StatusBadRequest},
+		{`{"method":"unknown"}`,
+			`{"id":null,"error":{"code":-32601,"message":"method unknown not found"}}`,
+			http.StatusNotFound},
+		{`{"jsonrpc":"2.0","method":"echo","params":{"value":"a"}}`, "", http.StatusNoContent},
+		{`[{"jsonrpc":"2.0","id":1,"method":"echo","params":{"value":"a"}},1]`,
+			`[{"id":1,"jsonrpc":"2.0","result":"a"},{"id":null,"error":{"code":-32600,"message":"request must be an object"}}]`,
+			http.StatusOK},
+	}
+	for _, c := range cases {
+		data, status := handleBody([]byte(c.body), testLookup)
+		response := marshalTestResponse(t, data)
+		if response != c.response || status != c.status {
+			t.Fatalf("request %s got %s %d, expect %s %d",
+				c.body, response, status, c.response, c.status)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package rpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+)
+
+const (
+	DefaultCertFile = "rpc.cert"
+	DefaultKeyFile  = "rpc.key"
+
+	selfSignedValidity = 10 * 365 * 24 * time.Hour
+)
+
+// certs holds the *certLoader once TLS enabled, it is stored by the server
+// and loaded by the SIGHUP handler concurrently.
+var certs atomic.Value
+
+// certLoader holds the server certificate, which can be reloaded while
+// the server is running.
+type certLoader struct {
+	sync.RWMutex
+	certFile string
+	keyFile  string
+	cert     *tls.Certificate
+}
+
+func (l *certLoader) load() error {
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	l.cert = &cert
+	return nil
+}
+
+func (l *certLoader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.cert, nil
+}
+
+// ReloadCertificate reloads the certificate and key files of the RPC server,
+// new connections will use the new certificate.
+func ReloadCertificate() error {
+	loader, ok := certs.Load().(*certLoader)
+	if !ok {
+		return errors.New("TLS not enabled")
+	}
+	if err := loader.load(); err != nil {
+		return err
+	}
+	log.Info("RPC server certificate reloaded")
+	return nil
+}
+
+var (
+	tlsOnce   sync.Once
+	sharedTLS *tls.Config
+	tlsErr    error
+)
+
+// TLSConfig returns the TLS config of RPCTLS, it is shared by the RPC and
+// gRPC servers so the certificate is reloaded for both. It returns nil if
+// TLS is not enabled.
+func TLSConfig() (*tls.Config, error) {
+	c := config.Values().RPCTLS
+	if c == nil {
+		return nil, nil
+	}
+	tlsOnce.Do(func() {
+		sharedTLS, tlsErr = newTLSConfig(c)
+	})
+	return sharedTLS, tlsErr
+}
+
+func newTLSConfig(c *config.TLS) (*tls.Config, error) {
+	loader := &certLoader{certFile: c.CertFile, keyFile: c.KeyFile}
+	if len(loader.certFile) == 0 {
+		loader.certFile = DefaultCertFile
+	}
+	if len(loader.keyFile) == 0 {
+		loader.keyFile = DefaultKeyFile
+	}
+
+	if !fileExists(loader.certFile) && !fileExists(loader.keyFile) {
+		log.Info("Generate self-signed certificate ", loader.certFile)
+		if err := generateCertificate(loader.certFile, loader.keyFile); err != nil {
+			return nil, err
+		}
+	}
+	if err := loader.load(); err != nil {
+		return nil, err
+	}
+	certs.Store(loader)
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: loader.getCertificate,
+	}
+
+	// Verify client certificates
+	if len(c.ClientCAFile) > 0 {
+		data, err := ioutil.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("no certificate found in " + c.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// generateCertificate creates a self-signed certificate for the local
+// host names and addresses.
+func generateCertificate(certFile, keyFile string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"SPV node self-signed"},
+			CommonName:   host,
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{host},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host != "localhost" {
+		template.DNSNames = append(template.DNSNames, "localhost")
+	}
+	if ip := net.ParseIP(config.Values().RPCBind); ip != nil && !ip.IsUnspecified() {
+		template.IPAddresses = append(template.IPAddresses, ip)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := ioutil.WriteFile(certFile, certPEM, 0644); err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+	return ioutil.WriteFile(keyFile, keyPEM, 0600)
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
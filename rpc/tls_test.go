@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+)
+
+func TestReloadCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := &config.TLS{
+		CertFile: filepath.Join(dir, "rpc.cert"),
+		KeyFile:  filepath.Join(dir, "rpc.key"),
+	}
+	tlsConfig, err := newTLSConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := tlsConfig.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := generateCertificate(c.CertFile, c.KeyFile); err != nil {
+		t.Fatal(err)
+	}
+
+	// Handshakes read the certificate while it is reloading
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if cert, err := tlsConfig.GetCertificate(nil); err != nil || cert == nil {
+					t.Error("no certificate during reload")
+					return
+				}
+			}
+		}()
+	}
+	if err := ReloadCertificate(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	second, err := tlsConfig.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first.Certificate[0], second.Certificate[0]) {
+		t.Fatal("certificate not reloaded")
+	}
+}
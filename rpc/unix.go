@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+)
+
+const (
+	DefaultSocketMode = 0600
+
+	// maxLineSize is the maximum size of a request line on raw socket.
+	maxLineSize = 4 * 1024 * 1024
+)
+
+// startUnixServer serves the RPC server on unix domain sockets, requests
+// through them are not authenticated, the access is controlled by the
+// socket file permissions.
+func startUnixServer(c *config.UnixSocket) error {
+	mode := os.FileMode(DefaultSocketMode)
+	if len(c.Mode) > 0 {
+		m, err := strconv.ParseUint(c.Mode, 8, 32)
+		if err != nil {
+			return err
+		}
+		mode = os.FileMode(m)
+	}
+
+	if len(c.Path) > 0 {
+		listener, err := listenUnix(c.Path, mode)
+		if err != nil {
+			return err
+		}
+		log.Debug("Start RPC server at unix socket:", c.Path)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+			handleWebSocket(w, r, nil)
+		})
+		mux.HandleFunc("/rest/", func(w http.ResponseWriter, r *http.Request) {
+			handleREST(w, r, nil)
+		})
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			handleHTTP(w, r, nil)
+		})
+		go func() {
+			if err := http.Serve(listener, mux); err != nil {
+				log.Error("Serve unix socket ", c.Path, ": ", err.Error())
+			}
+		}()
+	}
+
+	if len(c.RawPath) > 0 {
+		listener, err := listenUnix(c.RawPath, mode)
+		if err != nil {
+			return err
+		}
+		log.Debug("Start raw RPC server at unix socket:", c.RawPath)
+		go serveRaw(listener)
+	}
+
+	return nil
+}
+
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	// Remove the socket file left by last run
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+func serveRaw(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Error("Accept raw RPC connection error: ", err.Error())
+			return
+		}
+		go handleRaw(conn)
+	}
+}
+
+// handleRaw reads requests from the connection line by line, and writes
+// each response in one line. A line can be a single request or a batch.
+func handleRaw(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	writer := bufio.NewWriter(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		response, _ := handleBody(line, lookupMethod)
+		if response == nil {
+			continue
+		}
+		data, err := json.Marshal(response)
+		if err != nil {
+			log.Error("Marshal raw RPC response error: ", err.Error())
+			return
+		}
+		writer.Write(data)
+		writer.WriteByte('\n')
+		if err := writer.Flush(); err != nil {
+			return
+		}
+	}
+}
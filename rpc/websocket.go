@@ -0,0 +1,389 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+	"github.com/elastos/Elastos.ELA.SPV/sdk"
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Events can be subscribed through WebSocket.
+	EventNewBlock     = "newblock"
+	EventTransaction  = "transaction"
+	EventConfirmation = "confirmation"
+	EventRollback     = "rollback"
+	EventSyncState    = "syncstate"
+
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+	wsMaxMessage = 1024 * 1024
+	wsSendBuffer = 256
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+var hub = newWsHub()
+
+type subscription struct {
+	id            string
+	event         string
+	addrs         map[common.Uint168]struct{}
+	confirmations uint32
+}
+
+type wsSession struct {
+	sync.Mutex
+	conn          *websocket.Conn
+	send          chan []byte
+	quit          chan struct{}
+	subscriptions map[string]*subscription
+	lookup        func(string) (Method, bool)
+}
+
+func (s *wsSession) subscribe(sub *subscription) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.subscriptions[sub.id] = sub
+}
+
+func (s *wsSession) unsubscribe(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	_, ok := s.subscriptions[id]
+	delete(s.subscriptions, id)
+	return ok
+}
+
+func (s *wsSession) getSubscriptions(event string) []*subscription {
+	s.Lock()
+	defer s.Unlock()
+
+	var subs []*subscription
+	for _, sub := range s.subscriptions {
+		if sub.event == event {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// write queues the message to send, a session too slow to receive
+// messages will be closed.
+func (s *wsSession) write(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Error("WebSocket marshal message error ", err)
+		return
+	}
+	select {
+	case s.send <- data:
+	case <-s.quit:
+	default:
+		log.Warn("WebSocket session send buffer full, closing")
+		s.conn.Close()
+	}
+}
+
+func (s *wsSession) notify(sub *subscription, result interface{}) {
+	s.write(&Notification{
+		Version: "2.0",
+		Method:  "subscription",
+		Params: &SubscriptionResult{
+			Subscription: sub.id,
+			Event:        sub.event,
+			Result:       result,
+		},
+	})
+}
+
+func (s *wsSession) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data := <-s.send:
+			s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				s.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				s.conn.Close()
+				return
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *wsSession) readPump() {
+	s.conn.SetReadLimit(wsMaxMessage)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		return nil
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		s.handleMessage(data)
+	}
+}
+
+func (s *wsSession) handleMessage(data []byte) {
+	response, _ := handleBody(data, s.lookup)
+	if response != nil {
+		s.write(response)
+	}
+}
+
+// lookupMethod returns the session methods besides the common methods.
+func (s *wsSession) lookupMethod(name string) (Method, bool) {
+	switch name {
+	case "subscribe":
+		return s.handleSubscribe, true
+	case "unsubscribe":
+		return s.handleUnsubscribe, true
+	}
+	return lookupMethod(name)
+}
+
+func (s *wsSession) handleSubscribe(params Params) (Result, error) {
+	event, ok := params.String("event")
+	if !ok {
+		return nil, invalidParams("[Subscribe] parameter event not exist")
+	}
+
+	sub := &subscription{id: hub.newSubscriptionId(), event: event}
+	switch event {
+	case EventNewBlock, EventRollback, EventSyncState:
+	case EventTransaction:
+		if _, ok := params["addresses"]; ok {
+			addresses, ok := params.Strings("addresses")
+			if !ok {
+				return nil, invalidParams("[Subscribe] addresses not in string array format")
+			}
+			sub.addrs = make(map[common.Uint168]struct{})
+			for _, address := range addresses {
+				hash, err := common.Uint168FromAddress(address)
+				if err != nil {
+					return nil, invalidParams("[Subscribe] invalid address %s", address)
+				}
+				sub.addrs[*hash] = struct{}{}
+			}
+		}
+	case EventConfirmation:
+		confirmations, ok := params.Uint("confirmations")
+		if !ok || confirmations == 0 {
+			return nil, invalidParams("[Subscribe] parameter confirmations not exist or is zero")
+		}
+		sub.confirmations = confirmations
+	default:
+		return nil, invalidParams("[Subscribe] unknown event %s", event)
+	}
+
+	s.subscribe(sub)
+	return sub.id, nil
+}
+
+func (s *wsSession) handleUnsubscribe(params Params) (Result, error) {
+	id, ok := params.String("id")
+	if !ok {
+		return nil, invalidParams("[Unsubscribe] parameter id not exist")
+	}
+	return s.unsubscribe(id), nil
+}
+
+type wsHub struct {
+	sync.RWMutex
+	sessions map[*wsSession]struct{}
+	nextId   uint64
+}
+
+func newWsHub() *wsHub {
+	return &wsHub{sessions: make(map[*wsSession]struct{})}
+}
+
+func (h *wsHub) newSubscriptionId() string {
+	h.Lock()
+	defer h.Unlock()
+
+	h.nextId++
+	return strconv.FormatUint(h.nextId, 16)
+}
+
+func (h *wsHub) register(session *wsSession) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.sessions[session] = struct{}{}
+}
+
+func (h *wsHub) unregister(session *wsSession) {
+	h.Lock()
+	defer h.Unlock()
+
+	delete(h.sessions, session)
+}
+
+func (h *wsHub) getSessions() []*wsSession {
+	h.RLock()
+	defer h.RUnlock()
+
+	sessions := make([]*wsSession, 0, len(h.sessions))
+	for session := range h.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+func (h *wsHub) OnBlock(header *core.Header, txs []*core.Transaction, rescanned bool) {
+	var blockInfo *BlockInfo
+	var confirmed = make(map[uint32][]*ConfirmationInfo)
+	for _, session := range h.getSessions() {
+		for _, sub := range session.getSubscriptions(EventTransaction) {
+			for _, tx := range txs {
+				if sub.addrs == nil || Node.TxMatches(tx, sub.addrs) {
+					session.notify(sub, getTransactionInfo(header, tx))
+				}
+			}
+		}
+
+		// Blocks synchronized again by rescan have been notified
+		if rescanned {
+			continue
+		}
+
+		for _, sub := range session.getSubscriptions(EventNewBlock) {
+			if blockInfo == nil {
+				var err error
+				blockInfo, err = getBlockInfo(*header, false)
+				if err != nil {
+					log.Error("WebSocket get block info error ", err)
+					break
+				}
+			}
+			session.notify(sub, blockInfo)
+		}
+
+		for _, sub := range session.getSubscriptions(EventConfirmation) {
+			infos, ok := confirmed[sub.confirmations]
+			if !ok {
+				infos = getConfirmedTxs(header.Height, sub.confirmations)
+				confirmed[sub.confirmations] = infos
+			}
+			for _, info := range infos {
+				session.notify(sub, info)
+			}
+		}
+	}
+}
+
+func (h *wsHub) OnRollback(height uint32, txs []*core.Transaction) {
+	info := &RollbackInfo{Height: height, Transactions: make([]string, 0, len(txs))}
+	for _, tx := range txs {
+		info.Transactions = append(info.Transactions, common.BytesToHexString(tx.Hash().Bytes()))
+	}
+	for _, session := range h.getSessions() {
+		for _, sub := range session.getSubscriptions(EventRollback) {
+			session.notify(sub, info)
+		}
+	}
+}
+
+func (h *wsHub) OnStateChange(state sdk.ChainState) {
+	info := &SyncStateInfo{State: getSyncState(state)}
+	for _, session := range h.getSessions() {
+		for _, sub := range session.getSubscriptions(EventSyncState) {
+			session.notify(sub, info)
+		}
+	}
+}
+
+func HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	credential, ok := checkAuth(w, r)
+	if !ok {
+		return
+	}
+	handleWebSocket(w, r, credential)
+}
+
+func handleWebSocket(w http.ResponseWriter, r *http.Request, credential *credential) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Warn("WebSocket upgrade error ", err)
+		return
+	}
+
+	session := &wsSession{
+		conn:          conn,
+		send:          make(chan []byte, wsSendBuffer),
+		quit:          make(chan struct{}),
+		subscriptions: make(map[string]*subscription),
+	}
+	session.lookup = authorize(credential, r.RemoteAddr, session.lookupMethod)
+	hub.register(session)
+	go session.writePump()
+
+	session.readPump()
+
+	hub.unregister(session)
+	close(session.quit)
+	conn.Close()
+}
+
+// getConfirmedTxs returns the stored transactions just reached the
+// confirmations with the block on height committed.
+func getConfirmedTxs(height, confirmations uint32) []*ConfirmationInfo {
+	if height+1 <= confirmations {
+		return nil
+	}
+	txHeight := height + 1 - confirmations
+	txIds, err := Node.GetTxIds(txHeight)
+	if err != nil {
+		log.Error("WebSocket query transactions on height ", txHeight, " error ", err)
+		return nil
+	}
+
+	infos := make([]*ConfirmationInfo, 0, len(txIds))
+	for _, txId := range txIds {
+		infos = append(infos, &ConfirmationInfo{
+			TxID:          common.BytesToHexString(txId.Bytes()),
+			Height:        txHeight,
+			Confirmations: confirmations,
+		})
+	}
+	return infos
+}
+
+func getSyncState(state sdk.ChainState) string {
+	switch state {
+	case sdk.SYNCING:
+		return "syncing"
+	case sdk.WAITING:
+		return "synced"
+	default:
+		return "unknown"
+	}
+}
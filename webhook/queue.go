@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/boltdb/bolt"
+)
+
+var BKTWebhooks = []byte("Webhooks")
+
+type delivery struct {
+	Id          uint64
+	URL         string
+	Event       string
+	Payload     []byte
+	Attempts    uint32
+	NextAttempt int64
+}
+
+// queue persists the pending deliveries, so they survive node restarts.
+type queue struct {
+	db *bolt.DB
+}
+
+func newQueue(db *bolt.DB) (*queue, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(BKTWebhooks)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &queue{db: db}, nil
+}
+
+func (q *queue) push(url, event string, payload func(id uint64) ([]byte, error)) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(BKTWebhooks)
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		data, err := payload(id)
+		if err != nil {
+			return err
+		}
+
+		return putDelivery(bucket, &delivery{
+			Id:      id,
+			URL:     url,
+			Event:   event,
+			Payload: data,
+		})
+	})
+}
+
+// due returns the deliveries should be attempted at the given time.
+func (q *queue) due(now int64) (deliveries []*delivery, err error) {
+	err = q.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTWebhooks).ForEach(func(k, v []byte) error {
+			var d delivery
+			if err := json.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			if d.NextAttempt <= now {
+				deliveries = append(deliveries, &d)
+			}
+			return nil
+		})
+	})
+
+	return deliveries, err
+}
+
+func (q *queue) update(d *delivery) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return putDelivery(tx.Bucket(BKTWebhooks), d)
+	})
+}
+
+func (q *queue) remove(d *delivery) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(BKTWebhooks).Delete(deliveryKey(d.Id))
+	})
+}
+
+func putDelivery(bucket *bolt.Bucket, d *delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(deliveryKey(d.Id), data)
+}
+
+func deliveryKey(id uint64) []byte {
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], id)
+	return key[:]
+}
@@ -0,0 +1,301 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elastos/Elastos.ELA.SPV.Node/config"
+	"github.com/elastos/Elastos.ELA.SPV.Node/node"
+
+	"github.com/elastos/Elastos.ELA.SPV/log"
+	"github.com/elastos/Elastos.ELA.SPV/sdk"
+	"github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA/core"
+)
+
+const (
+	// Events can be delivered through webhooks.
+	EventTransaction  = "transaction"
+	EventConfirmation = "confirmation"
+	EventRollback     = "rollback"
+
+	deliverInterval = time.Second
+	deliverTimeout  = 10 * time.Second
+	retryBaseDelay  = 10 * time.Second
+	retryMaxDelay   = time.Hour
+	maxAttempts     = 30
+)
+
+type Payload struct {
+	Id    string      `json:"id"`
+	Event string      `json:"event"`
+	Time  int64       `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+type TransactionData struct {
+	TxID      string `json:"txid"`
+	Height    uint32 `json:"height"`
+	BlockHash string `json:"blockhash"`
+	RawTx     string `json:"rawtx"`
+}
+
+type ConfirmationData struct {
+	TxID          string `json:"txid"`
+	Height        uint32 `json:"height"`
+	Confirmations uint32 `json:"confirmations"`
+}
+
+type RollbackData struct {
+	Height       uint32   `json:"height"`
+	Transactions []string `json:"transactions"`
+}
+
+type Webhooks struct {
+	node   *node.SPVNode
+	hooks  []config.Webhook
+	queue  *queue
+	client *http.Client
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+func New(spvNode *node.SPVNode, hooks []config.Webhook) (*Webhooks, error) {
+	queue, err := newQueue(spvNode.DataStore.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := &Webhooks{
+		node:   spvNode,
+		hooks:  hooks,
+		queue:  queue,
+		client: &http.Client{Timeout: deliverTimeout},
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	spvNode.AddListener(webhooks)
+
+	return webhooks, nil
+}
+
+func (w *Webhooks) Start() {
+	go w.deliverHandler()
+}
+
+// Stop the deliveries and wait the delivering one finished, undelivered
+// events will be delivered after restart.
+func (w *Webhooks) Stop() {
+	close(w.quit)
+	<-w.done
+}
+
+func (w *Webhooks) OnBlock(header *core.Header, txs []*core.Transaction, rescanned bool) {
+	for _, hook := range w.hooks {
+		if subscribed(&hook, EventTransaction) {
+			for _, tx := range txs {
+				buf := new(bytes.Buffer)
+				if err := tx.Serialize(buf); err != nil {
+					log.Error("Webhook serialize transaction error ", err)
+					continue
+				}
+				w.push(&hook, EventTransaction, &TransactionData{
+					TxID:      common.BytesToHexString(tx.Hash().Bytes()),
+					Height:    header.Height,
+					BlockHash: header.Hash().String(),
+					RawTx:     common.BytesToHexString(buf.Bytes()),
+				})
+			}
+		}
+
+		if !rescanned && subscribed(&hook, EventConfirmation) && hook.Confirmations > 0 &&
+			header.Height+1 > hook.Confirmations {
+			height := header.Height + 1 - hook.Confirmations
+			txIds, err := w.node.GetTxIds(height)
+			if err != nil {
+				log.Error("Webhook query transactions on height ", height, " error ", err)
+				continue
+			}
+			for _, txId := range txIds {
+				w.push(&hook, EventConfirmation, &ConfirmationData{
+					TxID:          common.BytesToHexString(txId.Bytes()),
+					Height:        height,
+					Confirmations: hook.Confirmations,
+				})
+			}
+		}
+	}
+}
+
+func (w *Webhooks) OnRollback(height uint32, txs []*core.Transaction) {
+	data := &RollbackData{Height: height, Transactions: make([]string, 0, len(txs))}
+	for _, tx := range txs {
+		data.Transactions = append(data.Transactions, common.BytesToHexString(tx.Hash().Bytes()))
+	}
+	for _, hook := range w.hooks {
+		if subscribed(&hook, EventRollback) {
+			w.push(&hook, EventRollback, data)
+		}
+	}
+}
+
+func (w *Webhooks) OnStateChange(sdk.ChainState) {}
+
+func (w *Webhooks) push(hook *config.Webhook, event string, data interface{}) {
+	err := w.queue.push(hook.URL, event, func(id uint64) ([]byte, error) {
+		return json.Marshal(&Payload{
+			Id:    strconv.FormatUint(id, 10),
+			Event: event,
+			Time:  time.Now().Unix(),
+			Data:  data,
+		})
+	})
+	if err != nil {
+		log.Error("Webhook queue ", event, " event error ", err)
+	}
+}
+
+func (w *Webhooks) deliverHandler() {
+	ticker := time.NewTicker(deliverInterval)
+	defer ticker.Stop()
+	defer close(w.done)
+
+	for {
+		select {
+		case <-ticker.C:
+			deliveries, err := w.queue.due(time.Now().Unix())
+			if err != nil {
+				log.Error("Webhook query deliveries error ", err)
+				continue
+			}
+
+			// Deliver to each URL concurrently, so a dead endpoint does not
+			// stall the others
+			urls := make(map[string][]*delivery)
+			for _, d := range deliveries {
+				urls[d.URL] = append(urls[d.URL], d)
+			}
+			var wg sync.WaitGroup
+			for _, deliveries := range urls {
+				wg.Add(1)
+				go func(deliveries []*delivery) {
+					defer wg.Done()
+					w.deliverURL(deliveries)
+				}(deliveries)
+			}
+			wg.Wait()
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// deliverURL delivers the deliveries to the same URL in order, the rest are
+// left for the next tick after the first failure.
+func (w *Webhooks) deliverURL(deliveries []*delivery) {
+	for _, d := range deliveries {
+		select {
+		case <-w.quit:
+			return
+		default:
+		}
+		if !w.deliver(d) {
+			return
+		}
+	}
+}
+
+// deliver posts the delivery to the webhook, returns false if it failed and
+// will be retried later.
+func (w *Webhooks) deliver(d *delivery) bool {
+	hook := w.getHook(d.URL)
+	if hook == nil {
+		log.Warn("Webhook ", d.URL, " not configured any more, drop delivery ", d.Id)
+		w.queue.remove(d)
+		return true
+	}
+
+	err := w.post(hook, d)
+	if err == nil {
+		w.queue.remove(d)
+		return true
+	}
+
+	d.Attempts++
+	if d.Attempts >= maxAttempts {
+		log.Error("Webhook deliver ", d.Id, " to ", d.URL, " failed after ", d.Attempts, " attempts, drop it, ", err)
+		w.queue.remove(d)
+		return false
+	}
+
+	delay := retryBaseDelay << (d.Attempts - 1)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	d.NextAttempt = time.Now().Add(delay).Unix()
+	log.Warn("Webhook deliver ", d.Id, " to ", d.URL, " failed, retry in ", delay, ", ", err)
+	if err := w.queue.update(d); err != nil {
+		log.Error("Webhook update delivery error ", err)
+	}
+	return false
+}
+
+func (w *Webhooks) post(hook *config.Webhook, d *delivery) error {
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-SPV-Event", d.Event)
+	req.Header.Set("X-SPV-Delivery", strconv.FormatUint(d.Id, 10))
+	if hook.Secret != "" {
+		req.Header.Set("X-SPV-Signature", "sha256="+sign(hook.Secret, d.Payload))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("response status %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *Webhooks) getHook(url string) *config.Webhook {
+	for i := range w.hooks {
+		if w.hooks[i].URL == url {
+			return &w.hooks[i]
+		}
+	}
+	return nil
+}
+
+func subscribed(hook *config.Webhook, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// sign the payload with HMAC-SHA256 using the webhook secret.
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}